@@ -4,72 +4,162 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync/atomic"
 	"time"
 
 	"github.com/redis/go-redis/v9"
+	"go-careers/logging"
 )
 
 type RedisCache struct {
-	client *redis.Client
-	ctx    context.Context
+	client  *redis.Client
+	timeout time.Duration
+
+	hits   atomic.Uint64
+	misses atomic.Uint64
+	errors atomic.Uint64
+}
+
+// Stats counts cache lookups by outcome since the cache was created. Errors
+// include failed writes and deletes as well as failed lookups.
+type Stats struct {
+	Hits   uint64
+	Misses uint64
+	Errors uint64
 }
 
-func NewRedisCache(host, port string) (*RedisCache, error) {
+// NewRedisCache connects to Redis. Every cache operation is given at most
+// timeout, on top of the caller's context, so a slow cache cannot hold up a
+// request that could be served from the database; zero means no limit.
+func NewRedisCache(host, port string, timeout time.Duration) (*RedisCache, error) {
 	client := redis.NewClient(&redis.Options{
 		Addr:     fmt.Sprintf("%s:%s", host, port),
 		Password: "", // no password for local dev
 		DB:       0,
 	})
 
-	ctx := context.Background()
+	c := &RedisCache{
+		client:  client,
+		timeout: timeout,
+	}
 
 	// Test connection
+	ctx, cancel := c.withTimeout(context.Background())
+	defer cancel()
 	if err := client.Ping(ctx).Err(); err != nil {
 		return nil, fmt.Errorf("failed to connect to Redis: %w", err)
 	}
 
-	return &RedisCache{
-		client: client,
-		ctx:    ctx,
-	}, nil
+	return c, nil
+}
+
+func (c *RedisCache) withTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, c.timeout)
 }
 
 // Get retrieves a value from cache and unmarshals it into the provided interface
-func (c *RedisCache) Get(key string, dest interface{}) error {
-	val, err := c.client.Get(c.ctx, key).Result()
+func (c *RedisCache) Get(ctx context.Context, key string, dest interface{}) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	val, err := c.client.Get(ctx, key).Result()
 	if err == redis.Nil {
+		c.misses.Add(1)
+		logging.RecordCacheLookup(ctx, false)
 		return fmt.Errorf("cache miss")
 	} else if err != nil {
-		return err
+		logging.RecordCacheLookup(ctx, false)
+		return c.countError(err)
 	}
 
-	return json.Unmarshal([]byte(val), dest)
+	if err := json.Unmarshal([]byte(val), dest); err != nil {
+		logging.RecordCacheLookup(ctx, false)
+		return c.countError(err)
+	}
+	c.hits.Add(1)
+	logging.RecordCacheLookup(ctx, true)
+	return nil
 }
 
 // Set stores a value in cache with a TTL
-func (c *RedisCache) Set(key string, value interface{}, ttl time.Duration) error {
+func (c *RedisCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
 	json, err := json.Marshal(value)
 	if err != nil {
 		return err
 	}
 
-	return c.client.Set(c.ctx, key, json, ttl).Err()
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.countError(c.client.Set(ctx, key, json, ttl).Err())
 }
 
 // Delete removes a key from cache
-func (c *RedisCache) Delete(key string) error {
-	return c.client.Del(c.ctx, key).Err()
+func (c *RedisCache) Delete(ctx context.Context, key string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.countError(c.client.Del(ctx, key).Err())
 }
 
 // DeletePattern removes all keys matching a pattern
-func (c *RedisCache) DeletePattern(pattern string) error {
-	iter := c.client.Scan(c.ctx, 0, pattern, 0).Iterator()
-	for iter.Next(c.ctx) {
-		if err := c.client.Del(c.ctx, iter.Val()).Err(); err != nil {
-			return err
+func (c *RedisCache) DeletePattern(ctx context.Context, pattern string) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+
+	iter := c.client.Scan(ctx, 0, pattern, 0).Iterator()
+	for iter.Next(ctx) {
+		if err := c.client.Del(ctx, iter.Val()).Err(); err != nil {
+			return c.countError(err)
 		}
 	}
-	return iter.Err()
+	return c.countError(iter.Err())
+}
+
+// Incr increments the integer counter at key, which starts at zero, and
+// returns its new value.
+func (c *RedisCache) Incr(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	n, err := c.client.Incr(ctx, key).Result()
+	return n, c.countError(err)
+}
+
+// Counter returns the integer counter at key, or 0 if it was never
+// incremented. Reading a counter is not a lookup, so it counts as neither a
+// hit nor a miss.
+func (c *RedisCache) Counter(ctx context.Context, key string) (int64, error) {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	n, err := c.client.Get(ctx, key).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	}
+	return n, c.countError(err)
+}
+
+// Stats returns the lookup counts so far.
+func (c *RedisCache) Stats() Stats {
+	return Stats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Errors: c.errors.Load(),
+	}
+}
+
+func (c *RedisCache) countError(err error) error {
+	if err != nil {
+		c.errors.Add(1)
+	}
+	return err
+}
+
+// Ping checks that Redis is reachable, within the cache's timeout.
+func (c *RedisCache) Ping(ctx context.Context) error {
+	ctx, cancel := c.withTimeout(ctx)
+	defer cancel()
+	return c.client.Ping(ctx).Err()
 }
 
 // Close closes the Redis connection
@@ -0,0 +1,96 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go-careers/repository"
+)
+
+type ClusterHandler struct {
+	repo repository.OccupationStore
+}
+
+func NewClusterHandler(repo repository.OccupationStore) *ClusterHandler {
+	return &ClusterHandler{repo: repo}
+}
+
+func (h *ClusterHandler) GetClusters(w http.ResponseWriter, r *http.Request) {
+	clusters, err := h.repo.GetClusters(r.Context())
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve career clusters")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(clusters)
+}
+
+func (h *ClusterHandler) GetPathways(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, "Invalid cluster id: must be an integer", http.StatusBadRequest)
+		return
+	}
+
+	clusters, err := h.repo.GetClusters(r.Context())
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve career clusters")
+		return
+	}
+
+	found := -1
+	for i, c := range clusters {
+		if c.ID == id {
+			found = i
+			break
+		}
+	}
+	if found < 0 {
+		http.Error(w, "Career cluster not found", http.StatusNotFound)
+		return
+	}
+
+	pathways, err := h.repo.GetPathways(r.Context(), id)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve career pathways")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"cluster":  clusters[found],
+		"pathways": pathways,
+	})
+}
+
+func (h *ClusterHandler) GetPathwayOccupations(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	pathway, err := h.repo.GetPathway(r.Context(), id)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve career pathway")
+		return
+	}
+	if pathway == nil {
+		http.Error(w, "Career pathway not found", http.StatusNotFound)
+		return
+	}
+
+	occupations, err := h.repo.GetPathwayOccupations(r.Context(), id)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve occupations")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"pathway":     pathway,
+		"occupations": occupations,
+	})
+}
@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"go-careers/models"
+)
+
+func (h *OccupationHandler) GetSkills(w http.ResponseWriter, r *http.Request) {
+	h.getCompetencies(w, r, models.CompetencySkills)
+}
+
+func (h *OccupationHandler) GetKnowledge(w http.ResponseWriter, r *http.Request) {
+	h.getCompetencies(w, r, models.CompetencyKnowledge)
+}
+
+func (h *OccupationHandler) GetAbilities(w http.ResponseWriter, r *http.Request) {
+	h.getCompetencies(w, r, models.CompetencyAbilities)
+}
+
+// getCompetencies serves one competency collection for an occupation.
+// Supported query parameters:
+//   - sort: "importance" (default) or "level", always descending
+//   - min_importance: drop rows whose importance is below this value
+func (h *OccupationHandler) getCompetencies(w http.ResponseWriter, r *http.Request, kind models.CompetencyType) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	sortBy := r.URL.Query().Get("sort")
+	if sortBy == "" {
+		sortBy = "importance"
+	}
+	if sortBy != "importance" && sortBy != "level" {
+		http.Error(w, "Invalid sort parameter: must be 'importance' or 'level'", http.StatusBadRequest)
+		return
+	}
+
+	var minImportance float64
+	if v := r.URL.Query().Get("min_importance"); v != "" {
+		parsed, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			http.Error(w, "Invalid min_importance parameter: must be a number", http.StatusBadRequest)
+			return
+		}
+		minImportance = parsed
+	}
+
+	occ, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve occupation")
+		return
+	}
+	if occ == nil {
+		http.Error(w, "Occupation not found", http.StatusNotFound)
+		return
+	}
+
+	competencies, err := h.repo.GetCompetencies(r.Context(), id, kind)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve "+string(kind))
+		return
+	}
+
+	filtered := make([]models.Competency, 0, len(competencies))
+	for _, c := range competencies {
+		if c.Importance >= minImportance {
+			filtered = append(filtered, c)
+		}
+	}
+
+	sort.SliceStable(filtered, func(i, j int) bool {
+		if sortBy == "level" {
+			return filtered[i].Level > filtered[j].Level
+		}
+		return filtered[i].Importance > filtered[j].Importance
+	})
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(filtered)
+}
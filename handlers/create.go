@@ -1,24 +1,52 @@
 package handlers
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 
+	"go-careers/logging"
 	"go-careers/models"
 	"go-careers/repository"
 )
 
 type CreateCareersHandler struct {
-	repo *repository.OccupationRepository
+	repo repository.OccupationStore
 }
 
-func NewCreateCareersHandler(repo *repository.OccupationRepository) *CreateCareersHandler {
+func NewCreateCareersHandler(repo repository.OccupationStore) *CreateCareersHandler {
 	return &CreateCareersHandler{repo: repo}
 }
 
+// CreateBatch writes an array of occupation records in the camelCase format
+// of seed_data/occupations.jsonl, including tasks, competencies, alternate
+// titles and similarity lists. The snake_case names of the core fields are
+// also accepted. Supported query parameters:
+//   - mode: "insert" (default) fails items that already exist, "upsert"
+//     replaces them and "skip_existing" leaves them untouched
+//   - atomic: "true" (default) writes nothing unless every item succeeds;
+//     "false" writes each valid item on its own
+//
+// The response reports the outcome of every item by index.
 func (h *CreateCareersHandler) CreateBatch(w http.ResponseWriter, r *http.Request) {
-	var occupations []models.Occupation
+	mode, err := models.ParseBatchMode(r.URL.Query().Get("mode"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	atomic := true
+	if v := r.URL.Query().Get("atomic"); v != "" {
+		if atomic, err = strconv.ParseBool(v); err != nil {
+			http.Error(w, "Invalid atomic parameter: must be 'true' or 'false'", http.StatusBadRequest)
+			return
+		}
+	}
+
+	var occupations []models.OccupationRecord
 
 	if err := json.NewDecoder(r.Body).Decode(&occupations); err != nil {
 		http.Error(w, fmt.Sprintf("Invalid JSON: %s", err.Error()), http.StatusBadRequest)
@@ -30,26 +58,49 @@ func (h *CreateCareersHandler) CreateBatch(w http.ResponseWriter, r *http.Reques
 		return
 	}
 
-	// Validate each occupation
-	for i, occ := range occupations {
-		if err := occ.Validate(); err != nil {
-			http.Error(w, fmt.Sprintf("Validation error at index %d: %s", i, err.Error()), http.StatusBadRequest)
-			return
-		}
+	// Write batch; validation failures are reported per item
+	results, err := h.repo.CreateBatch(r.Context(), occupations, repository.BatchOptions{Mode: mode, Atomic: atomic})
+	aborted := err != nil && !errors.Is(err, repository.ErrBatchRolledBack)
+	if aborted && results == nil {
+		repoError(w, r, err, "Failed to create occupations")
+		return
 	}
 
-	// Insert batch
-	if err := h.repo.CreateBatch(occupations); err != nil {
-		// Log the actual error for debugging
-		fmt.Printf("Database error creating occupations: %v\n", err)
-		http.Error(w, "Failed to create occupations", http.StatusInternalServerError)
-		return
+	counts := map[string]int{}
+	for _, result := range results {
+		counts[result.Status]++
+	}
+
+	// 500 (504 on timeout) when a non-atomic batch was aborted part way,
+	// 422 when an atomic batch was rolled back, 207 when only some items
+	// were written
+	status, message := http.StatusOK, "Batch processed"
+	switch {
+	case aborted:
+		logging.Logger(r.Context()).Error("Batch aborted", "error", err)
+		status, message = http.StatusInternalServerError, "Batch aborted: only the items reported as written were written"
+		if errors.Is(err, context.DeadlineExceeded) {
+			status = http.StatusGatewayTimeout
+		}
+	case err != nil:
+		status, message = http.StatusUnprocessableEntity, "Batch rolled back: no occupations were written"
+	case counts[models.BatchFailed] > 0:
+		status, message = http.StatusMultiStatus, "Batch partially processed"
+	case counts[models.BatchCreated] > 0:
+		status = http.StatusCreated
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusCreated)
+	w.WriteHeader(status)
 	json.NewEncoder(w).Encode(map[string]interface{}{
-		"message": "Successfully created occupations",
+		"message": message,
+		"mode":    mode,
+		"atomic":  atomic,
 		"count":   len(occupations),
+		"created": counts[models.BatchCreated],
+		"updated": counts[models.BatchUpdated],
+		"skipped": counts[models.BatchSkipped],
+		"failed":  counts[models.BatchFailed],
+		"results": results,
 	})
 }
@@ -0,0 +1,21 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"go-careers/logging"
+)
+
+// repoError logs an unexpected repository error with the request's id and
+// writes the response: 504 when the operation ran out of time, otherwise 500
+// with message.
+func repoError(w http.ResponseWriter, r *http.Request, err error, message string) {
+	logging.Logger(r.Context()).Error(message, "error", err)
+	if errors.Is(err, context.DeadlineExceeded) {
+		http.Error(w, "Request timed out", http.StatusGatewayTimeout)
+		return
+	}
+	http.Error(w, message, http.StatusInternalServerError)
+}
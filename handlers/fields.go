@@ -0,0 +1,71 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"go-careers/models"
+)
+
+// occupationFields lists the JSON field names of models.Occupation that may be
+// requested through the fields= projection parameter.
+var occupationFields = jsonFieldNames(reflect.TypeOf(models.Occupation{}))
+
+func jsonFieldNames(t reflect.Type) map[string]bool {
+	names := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		name := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// parseFields validates a comma-separated fields= parameter. An empty value
+// means no projection and returns nil.
+func parseFields(v string) ([]string, error) {
+	if v == "" {
+		return nil, nil
+	}
+
+	fields := []string{"id"}
+	for _, f := range strings.Split(v, ",") {
+		f = strings.TrimSpace(f)
+		if f == "" || f == "id" {
+			continue
+		}
+		if !occupationFields[f] {
+			return nil, fmt.Errorf("Invalid fields parameter: unknown field '%s'", f)
+		}
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// projectFields reduces each item to the requested JSON fields. The id is
+// always kept so projected items remain addressable.
+func projectFields(items interface{}, fields []string) ([]map[string]json.RawMessage, error) {
+	b, err := json.Marshal(items)
+	if err != nil {
+		return nil, err
+	}
+
+	var full []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &full); err != nil {
+		return nil, err
+	}
+
+	projected := make([]map[string]json.RawMessage, len(full))
+	for i, item := range full {
+		projected[i] = make(map[string]json.RawMessage, len(fields))
+		for _, f := range fields {
+			if v, ok := item[f]; ok {
+				projected[i][f] = v
+			}
+		}
+	}
+	return projected, nil
+}
@@ -0,0 +1,54 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go-careers/models"
+)
+
+// GetGap compares the skills, knowledge and abilities of two occupations and
+// reports what a worker moving from the first to the second needs to raise.
+func (h *OccupationHandler) GetGap(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	occupations := make([]*models.Occupation, 2)
+	for i, id := range []string{vars["from"], vars["to"]} {
+		occ, err := h.repo.GetByID(r.Context(), id)
+		if err != nil {
+			repoError(w, r, err, "Failed to retrieve occupation")
+			return
+		}
+		if occ == nil {
+			http.Error(w, "Occupation not found: "+id, http.StatusNotFound)
+			return
+		}
+		occupations[i] = occ
+	}
+	from, to := occupations[0], occupations[1]
+
+	reports := map[models.CompetencyType]models.CompetencyGapReport{}
+	for _, kind := range []models.CompetencyType{models.CompetencySkills, models.CompetencyKnowledge, models.CompetencyAbilities} {
+		fromCompetencies, err := h.repo.GetCompetencies(r.Context(), from.ID, kind)
+		if err != nil {
+			repoError(w, r, err, "Failed to retrieve "+string(kind))
+			return
+		}
+		toCompetencies, err := h.repo.GetCompetencies(r.Context(), to.ID, kind)
+		if err != nil {
+			repoError(w, r, err, "Failed to retrieve "+string(kind))
+			return
+		}
+		reports[kind] = models.CompareCompetencies(fromCompetencies, toCompetencies)
+	}
+
+	gap := models.NewCareerGap(*from, *to,
+		reports[models.CompetencySkills],
+		reports[models.CompetencyKnowledge],
+		reports[models.CompetencyAbilities],
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(gap)
+}
@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"go-careers/repository"
+)
+
+// newTestRouter serves the occupation, search and create handlers from a
+// MemoryStore loaded with the seed export.
+func newTestRouter(t *testing.T) *mux.Router {
+	t.Helper()
+	store, err := repository.NewMemoryStore("../seed_data/occupations.jsonl", "../seed_data/career_clusters.json")
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+
+	occupations := NewOccupationHandler(store)
+	search := NewSearchHandler(store)
+	create := NewCreateCareersHandler(store)
+
+	r := mux.NewRouter()
+	r.HandleFunc("/search", search.Search).Methods("GET")
+	r.HandleFunc("/occupations", occupations.GetAll).Methods("GET")
+	r.HandleFunc("/occupations", create.CreateBatch).Methods("POST")
+	r.HandleFunc("/occupations/by-slug/{slug}", occupations.GetBySlug).Methods("GET")
+	r.HandleFunc("/occupations/{id}", occupations.GetByID).Methods("GET")
+	r.HandleFunc("/occupations/{id}", occupations.Patch).Methods("PATCH")
+	r.HandleFunc("/occupations/{id}", occupations.Delete).Methods("DELETE")
+	r.HandleFunc("/occupations/{id}/tasks", occupations.GetTasks).Methods("GET")
+	return r
+}
+
+func serve(r http.Handler, method, target, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	return rec
+}
+
+const newOccupation = `{"id":"99-0001.00","socId":"99-0001","socTitle":"Widget Calibrators","title":"Widget Calibrators","singularTitle":"Widget Calibrator","description":"Calibrate widgets."}`
+
+func TestHandlerStatuses(t *testing.T) {
+	r := newTestRouter(t)
+
+	tests := []struct {
+		method, target, body string
+		want                 int
+	}{
+		{"GET", "/occupations/11-1011.00", "", http.StatusOK},
+		{"GET", "/occupations/99-9999.00", "", http.StatusNotFound},
+		{"GET", "/occupations/by-slug/chief-executive", "", http.StatusOK},
+		{"GET", "/occupations?limit=5&sort=title", "", http.StatusOK},
+		{"GET", "/occupations?sort=salary", "", http.StatusBadRequest},
+		{"GET", "/occupations?limit=0", "", http.StatusBadRequest},
+		{"GET", "/occupations?cursor=garbage", "", http.StatusBadRequest},
+		{"GET", "/occupations/11-1011.00/tasks?offset=10000&limit=100", "", http.StatusOK},
+		{"GET", "/occupations/11-1011.00/tasks?offset=10001", "", http.StatusBadRequest},
+		{"GET", "/search?q=manager", "", http.StatusOK},
+		{"GET", "/search", "", http.StatusBadRequest},
+		{"PATCH", "/occupations/11-1011.00", `{"foo":1}`, http.StatusBadRequest},
+		{"PATCH", "/occupations/99-9999.00", `{"title":"X"}`, http.StatusNotFound},
+		{"PATCH", "/occupations/11-1011.00", `{"title":"Chief Executive Officers"}`, http.StatusOK},
+		{"POST", "/occupations", `[]`, http.StatusBadRequest},
+		{"POST", "/occupations", `{`, http.StatusBadRequest},
+		{"POST", "/occupations?mode=replace", `[` + newOccupation + `]`, http.StatusBadRequest},
+		{"POST", "/occupations", `[` + newOccupation + `]`, http.StatusCreated},
+		{"POST", "/occupations", `[` + newOccupation + `]`, http.StatusUnprocessableEntity},
+		{"POST", "/occupations?atomic=false&mode=skip_existing", `[` + newOccupation + `,{"id":"99-0002.00"}]`, http.StatusMultiStatus},
+		{"POST", "/occupations?mode=upsert", `[` + newOccupation + `]`, http.StatusOK},
+		{"DELETE", "/occupations/99-0001.00", "", http.StatusNoContent},
+		{"DELETE", "/occupations/99-0001.00", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		if rec := serve(r, tt.method, tt.target, tt.body); rec.Code != tt.want {
+			t.Errorf("%s %s = %d %q, want %d", tt.method, tt.target, rec.Code, rec.Body.String(), tt.want)
+		}
+	}
+}
+
+func TestGetAllFollowsNext(t *testing.T) {
+	r := newTestRouter(t)
+
+	seen := 0
+	target := "/occupations?limit=40&fields=id,title"
+	for target != "" {
+		rec := serve(r, "GET", target, "")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("GET %s = %d", target, rec.Code)
+		}
+		var body struct {
+			Data []map[string]interface{} `json:"data"`
+			Next *string                  `json:"next"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+			t.Fatalf("GET %s: %v", target, err)
+		}
+		for _, occ := range body.Data {
+			if len(occ) != 2 {
+				t.Fatalf("fields=id,title returned %v", occ)
+			}
+		}
+		seen += len(body.Data)
+
+		target = ""
+		if body.Next != nil {
+			target = *body.Next
+		}
+	}
+	if seen != 100 {
+		t.Errorf("followed next through %d occupations, want 100", seen)
+	}
+}
+
+func TestSearchDidYouMean(t *testing.T) {
+	r := newTestRouter(t)
+
+	rec := serve(r, "GET", "/search?q=finacial+analist", "")
+	var body struct {
+		Total      int    `json:"total"`
+		DidYouMean string `json:"did_you_mean"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decoding %q: %v", rec.Body.String(), err)
+	}
+	if body.Total != 0 || body.DidYouMean != "financial analyst" {
+		t.Errorf("search = %d results, did_you_mean %q, want 0 and %q", body.Total, body.DidYouMean, "financial analyst")
+	}
+}
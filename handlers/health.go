@@ -0,0 +1,134 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go-careers/logging"
+)
+
+// Readiness statuses, from best to worst. A service is degraded when only
+// optional dependencies (such as the cache) are down: it still answers, more
+// slowly.
+const (
+	StatusOK          = "ok"
+	StatusDegraded    = "degraded"
+	StatusUnavailable = "unavailable"
+	StatusDown        = "down"
+)
+
+// Check is a dependency that readiness depends on.
+type Check struct {
+	Name string
+	// Required dependencies make the service unavailable when down;
+	// others only degrade it
+	Required bool
+	Ping     func(ctx context.Context) error
+}
+
+// CheckResult is the outcome of one Check.
+type CheckResult struct {
+	Status    string  `json:"status"`
+	Required  bool    `json:"required"`
+	LatencyMs float64 `json:"latency_ms"`
+	Error     string  `json:"error,omitempty"`
+}
+
+type HealthHandler struct {
+	checks  []Check
+	timeout time.Duration
+	ready   atomic.Bool
+}
+
+// NewHealthHandler returns a handler whose readiness pings each check with
+// the given timeout. It reports not ready until SetReady(true) is called.
+func NewHealthHandler(timeout time.Duration, checks ...Check) *HealthHandler {
+	return &HealthHandler{checks: checks, timeout: timeout}
+}
+
+// SetReady marks the service as accepting traffic or, during shutdown, not.
+func (h *HealthHandler) SetReady(ready bool) {
+	h.ready.Store(ready)
+}
+
+// Livez reports that the process is up and serving. It never checks
+// dependencies, so a database outage does not get the process restarted.
+func (h *HealthHandler) Livez(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]string{"status": StatusOK})
+}
+
+// Readyz pings every dependency concurrently and reports each one's status
+// and latency. It responds 503 when a required dependency is down or the
+// server is shutting down, and 200 otherwise, including when degraded.
+func (h *HealthHandler) Readyz(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	if !h.ready.Load() {
+		w.WriteHeader(http.StatusServiceUnavailable)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"status": StatusUnavailable,
+			"reason": "shutting down",
+		})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), h.timeout)
+	defer cancel()
+
+	results := make([]CheckResult, len(h.checks))
+	var wg sync.WaitGroup
+	for i, check := range h.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = runCheck(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	status := StatusOK
+	checks := map[string]CheckResult{}
+	for i, check := range h.checks {
+		checks[check.Name] = results[i]
+		if results[i].Status == StatusDown {
+			if check.Required {
+				status = StatusUnavailable
+			} else if status == StatusOK {
+				status = StatusDegraded
+			}
+		}
+	}
+
+	if status == StatusUnavailable {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status": status,
+		"checks": checks,
+	})
+}
+
+func runCheck(ctx context.Context, check Check) CheckResult {
+	start := time.Now()
+	err := check.Ping(ctx)
+	result := CheckResult{
+		Status:    StatusOK,
+		Required:  check.Required,
+		LatencyMs: float64(time.Since(start).Microseconds()) / 1000,
+	}
+	if err != nil {
+		// Details such as hosts stay in the log
+		logging.Logger(ctx).Warn("Readiness check failed", "check", check.Name, "error", err)
+		result.Status = StatusDown
+		result.Error = "ping failed"
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = "timed out"
+		}
+	}
+	return result
+}
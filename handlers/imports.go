@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/mux"
+	"go-careers/importer"
+	"go-careers/models"
+)
+
+// maxImportBytes caps an import upload. The route is exempt from the global
+// request size limit.
+const maxImportBytes = 256 << 20
+
+type ImportHandler struct {
+	imports *importer.Manager
+}
+
+func NewImportHandler(imports *importer.Manager) *ImportHandler {
+	return &ImportHandler{imports: imports}
+}
+
+// Import imports application/x-ndjson occupations, one camelCase record per
+// line as in seed_data/occupations.jsonl. The mode query parameter works as
+// for POST /occupations. It responds 202 with the job, whose Location can be
+// polled for progress, as soon as the upload starts, then writes the
+// occupations chunk by chunk as the rest of the body arrives.
+func (h *ImportHandler) Import(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/x-ndjson" {
+		http.Error(w, "Unsupported Content-Type: must be application/x-ndjson", http.StatusUnsupportedMediaType)
+		return
+	}
+
+	mode, err := models.ParseBatchMode(r.URL.Query().Get("mode"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// A body without a length that turns out too large fails the job instead
+	if r.ContentLength > maxImportBytes {
+		http.Error(w, fmt.Sprintf("Request body too large: imports are limited to %d MB", maxImportBytes>>20), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	// Large uploads can take longer than the server's read and write
+	// timeouts allow, and the body is still read after the response is
+	// sent. EnableFullDuplex only fails for writers that do not support it,
+	// such as in tests
+	rc := http.NewResponseController(w)
+	rc.SetReadDeadline(time.Time{})
+	rc.SetWriteDeadline(time.Time{})
+	rc.EnableFullDuplex()
+
+	job := h.imports.Start(mode, r.ContentLength)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Location", "/imports/"+job.ID)
+	w.WriteHeader(http.StatusAccepted)
+	json.NewEncoder(w).Encode(job)
+	rc.Flush()
+
+	h.imports.Run(r.Context(), job.ID, http.MaxBytesReader(w, r.Body, maxImportBytes))
+}
+
+// GetImport reports the progress of an import job.
+func (h *ImportHandler) GetImport(w http.ResponseWriter, r *http.Request) {
+	job, ok := h.imports.Get(mux.Vars(r)["id"])
+	if !ok {
+		http.Error(w, "Import not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(job)
+}
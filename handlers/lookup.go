@@ -0,0 +1,53 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *OccupationHandler) GetBySlug(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	slug := vars["slug"]
+
+	occ, err := h.repo.GetBySlug(r.Context(), slug)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve occupation")
+		return
+	}
+
+	if occ == nil {
+		http.Error(w, "Occupation not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(occ)
+}
+
+// GetBySocID returns the detailed O*NET occupations filed under a SOC code;
+// one SOC code may cover several, e.g. 11-1011 covers Chief Executives and
+// Chief Sustainability Officers.
+func (h *OccupationHandler) GetBySocID(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	socID := vars["socId"]
+
+	occupations, err := h.repo.GetBySocID(r.Context(), socID)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve occupations")
+		return
+	}
+
+	if len(occupations) == 0 {
+		http.Error(w, "SOC code not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"soc_id":      socID,
+		"soc_title":   occupations[0].SocTitle,
+		"occupations": occupations,
+	})
+}
@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"go-careers/models"
+	"go-careers/repository"
+)
+
+type MatchHandler struct {
+	repo repository.OccupationStore
+}
+
+func NewMatchHandler(repo repository.OccupationStore) *MatchHandler {
+	return &MatchHandler{repo: repo}
+}
+
+// MatchInterests ranks occupations against a RIASEC interest profile posted
+// as {"R": 1.5, "I": 3, "A": 0, "S": 6, "E": 4.2, "C": 2}. Supported query
+// parameters:
+//   - method: "cosine" (default) or "correlation"
+//   - limit: number of occupations to return, 1-100 (default 20)
+func (h *MatchHandler) MatchInterests(w http.ResponseWriter, r *http.Request) {
+	method := r.URL.Query().Get("method")
+	if method == "" {
+		method = models.SimilarityCosine
+	}
+	if method != models.SimilarityCosine && method != models.SimilarityCorrelation {
+		http.Error(w, "Invalid method parameter: must be 'cosine' or 'correlation'", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := intParam(r, "limit", 20, 1, 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var scores map[string]*float64
+	if err := json.NewDecoder(r.Body).Decode(&scores); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+
+	for _, dim := range []string{"R", "I", "A", "S", "E", "C"} {
+		if scores[dim] == nil {
+			http.Error(w, fmt.Sprintf("Validation error: missing interest score '%s'", dim), http.StatusBadRequest)
+			return
+		}
+	}
+	profile := models.InterestProfile{
+		R: *scores["R"],
+		I: *scores["I"],
+		A: *scores["A"],
+		S: *scores["S"],
+		E: *scores["E"],
+		C: *scores["C"],
+	}
+	if err := profile.Validate(); err != nil {
+		http.Error(w, fmt.Sprintf("Validation error: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if _, _, ok := profile.Similarity(profile, method); !ok {
+		http.Error(w, "Validation error: correlation needs interest scores that are not all equal", http.StatusBadRequest)
+		return
+	}
+
+	matches, err := h.repo.MatchInterests(r.Context(), profile, method, limit)
+	if err != nil {
+		repoError(w, r, err, "Failed to match interests")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"method":  method,
+		"profile": profile,
+		"matches": matches,
+	})
+}
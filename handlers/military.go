@@ -0,0 +1,75 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"go-careers/models"
+	"go-careers/repository"
+)
+
+type MilitaryHandler struct {
+	repo repository.OccupationStore
+}
+
+func NewMilitaryHandler(repo repository.OccupationStore) *MilitaryHandler {
+	return &MilitaryHandler{repo: repo}
+}
+
+// GetOccupations returns the civilian occupations a military occupation code
+// such as "N_O_111" crosswalks to, along with the decoded code.
+func (h *MilitaryHandler) GetOccupations(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+
+	moc, err := models.ParseMOC(strings.ToUpper(vars["moc"]))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("Invalid military occupation code: %s", vars["moc"]), http.StatusBadRequest)
+		return
+	}
+
+	occupations, err := h.repo.GetByMilitaryCode(r.Context(), moc.Code)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve occupations")
+		return
+	}
+
+	if len(occupations) == 0 {
+		http.Error(w, "Military occupation code not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"moc":         moc,
+		"occupations": occupations,
+	})
+}
+
+// GetMilitaryCodes returns the military occupation codes that crosswalk to
+// an occupation.
+func (h *OccupationHandler) GetMilitaryCodes(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	occ, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve occupation")
+		return
+	}
+	if occ == nil {
+		http.Error(w, "Occupation not found", http.StatusNotFound)
+		return
+	}
+
+	codes, err := h.repo.GetMilitaryCodes(r.Context(), id)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve military codes")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(codes)
+}
@@ -2,38 +2,105 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
 
 	"github.com/gorilla/mux"
+	"go-careers/models"
 	"go-careers/repository"
 )
 
 type OccupationHandler struct {
-	repo *repository.OccupationRepository
+	repo repository.OccupationStore
 }
 
-func NewOccupationHandler(repo *repository.OccupationRepository) *OccupationHandler {
+func NewOccupationHandler(repo repository.OccupationStore) *OccupationHandler {
 	return &OccupationHandler{repo: repo}
 }
 
+// GetAll lists occupations one page at a time. Supported query parameters:
+//   - limit: page size, 1-100 (default 20)
+//   - cursor: opaque cursor taken from the previous page's next_cursor
+//   - sort: "id" (default), "title" or "soc_id"
+//   - fields: comma-separated list of fields to return
+//   - education, max_education: filter by typical education level, e.g.
+//     max_education=associate
 func (h *OccupationHandler) GetAll(w http.ResponseWriter, r *http.Request) {
-	occupations, err := h.repo.GetAll()
+	limit, err := intParam(r, "limit", 20, 1, 100)
 	if err != nil {
-		http.Error(w, "Failed to retrieve occupations", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
+	sort := r.URL.Query().Get("sort")
+	if sort != "" && sort != "id" && sort != "title" && sort != "soc_id" {
+		http.Error(w, "Invalid sort parameter: must be 'id', 'title' or 'soc_id'", http.StatusBadRequest)
+		return
+	}
+
+	fields, err := parseFields(r.URL.Query().Get("fields"))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	education, err := educationParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	page, err := h.repo.GetAll(r.Context(), repository.ListOptions{
+		Limit:     limit,
+		Sort:      sort,
+		Cursor:    r.URL.Query().Get("cursor"),
+		Education: education,
+	})
+	if errors.Is(err, repository.ErrInvalidCursor) {
+		http.Error(w, "Invalid cursor parameter", http.StatusBadRequest)
+		return
+	}
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve occupations")
+		return
+	}
+
+	var data interface{} = page.Occupations
+	if fields != nil {
+		if data, err = projectFields(page.Occupations, fields); err != nil {
+			http.Error(w, "Failed to retrieve occupations", http.StatusInternalServerError)
+			return
+		}
+	}
+
+	// The next link repeats the current query with the cursor advanced
+	var next, nextCursor interface{}
+	if page.NextCursor != "" {
+		u := *r.URL
+		q := u.Query()
+		q.Set("cursor", page.NextCursor)
+		u.RawQuery = q.Encode()
+		next = u.RequestURI()
+		nextCursor = page.NextCursor
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(occupations)
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"data":        data,
+		"total":       page.Total,
+		"limit":       limit,
+		"next_cursor": nextCursor,
+		"next":        next,
+	})
 }
 
 func (h *OccupationHandler) GetByID(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	occ, err := h.repo.GetByID(id)
+	occ, err := h.repo.GetByID(r.Context(), id)
 	if err != nil {
-		http.Error(w, "Failed to retrieve occupation", http.StatusInternalServerError)
+		repoError(w, r, err, "Failed to retrieve occupation")
 		return
 	}
 
@@ -50,9 +117,18 @@ func (h *OccupationHandler) GetSimilar(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
 	id := vars["id"]
 
-	similar, err := h.repo.GetSimilar(id)
+	by := r.URL.Query().Get("by")
+	if by == "" {
+		by = models.SimilarOccs
+	}
+	if _, ok := models.SimilarityLists[by]; !ok && by != models.SimilarAll {
+		http.Error(w, "Invalid by parameter: must be 'occs', 'interests', 'skills' or 'all'", http.StatusBadRequest)
+		return
+	}
+
+	similar, err := h.repo.GetSimilar(r.Context(), id, by)
 	if err != nil {
-		http.Error(w, "Failed to retrieve similar occupations", http.StatusInternalServerError)
+		repoError(w, r, err, "Failed to retrieve similar occupations")
 		return
 	}
 
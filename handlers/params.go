@@ -0,0 +1,48 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"go-careers/models"
+)
+
+// intParam reads an integer query parameter, falling back to def when it is
+// absent and rejecting values outside [min, max].
+func intParam(r *http.Request, name string, def, min, max int) (int, error) {
+	v := r.URL.Query().Get(name)
+	if v == "" {
+		return def, nil
+	}
+
+	n, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("Invalid %s parameter: must be an integer", name)
+	}
+	if n < min || n > max {
+		return 0, fmt.Errorf("Invalid %s parameter: must be between %d and %d", name, min, max)
+	}
+
+	return n, nil
+}
+
+// educationParams reads the education= (exact level) and max_education=
+// (highest level) filters, e.g. max_education=associate.
+func educationParams(r *http.Request) (models.EducationFilter, error) {
+	var filter models.EducationFilter
+
+	for name, dest := range map[string]*models.EducationLevel{"education": &filter.Level, "max_education": &filter.Max} {
+		v := r.URL.Query().Get(name)
+		if v == "" {
+			continue
+		}
+		level, ok := models.ParseEducationLevel(v)
+		if !ok {
+			return filter, fmt.Errorf("Invalid %s parameter: must be one of high_school, certificate, some_college, associate, bachelor, master, doctorate", name)
+		}
+		*dest = level
+	}
+
+	return filter, nil
+}
@@ -8,10 +8,10 @@ import (
 )
 
 type SearchHandler struct {
-	repo *repository.OccupationRepository
+	repo repository.OccupationStore
 }
 
-func NewSearchHandler(repo *repository.OccupationRepository) *SearchHandler {
+func NewSearchHandler(repo repository.OccupationStore) *SearchHandler {
 	return &SearchHandler{repo: repo}
 }
 
@@ -23,12 +23,48 @@ func (h *SearchHandler) Search(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	results, err := h.repo.Search(query)
+	limit, err := intParam(r, "limit", 20, 1, 100)
 	if err != nil {
-		http.Error(w, "Search failed", http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	offset, err := intParam(r, "offset", 0, 0, 10000)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	education, err := educationParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	fuzzy := r.URL.Query().Get("fuzzy") == "true"
+
+	page, err := h.repo.Search(r.Context(), repository.SearchOptions{
+		Query:     query,
+		Limit:     limit,
+		Offset:    offset,
+		Fuzzy:     fuzzy,
+		Education: education,
+	})
+	if err != nil {
+		repoError(w, r, err, "Search failed")
 		return
 	}
 
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(results)
+	response := map[string]interface{}{
+		"query":   query,
+		"fuzzy":   fuzzy,
+		"total":   page.Total,
+		"limit":   limit,
+		"offset":  offset,
+		"results": page.Results,
+	}
+	if page.DidYouMean != "" {
+		response["did_you_mean"] = page.DidYouMean
+	}
+	json.NewEncoder(w).Encode(response)
 }
@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go-careers/models"
+	"go-careers/repository"
+)
+
+type SocHandler struct {
+	repo repository.OccupationStore
+}
+
+func NewSocHandler(repo repository.OccupationStore) *SocHandler {
+	return &SocHandler{repo: repo}
+}
+
+func (h *SocHandler) GetMajorGroups(w http.ResponseWriter, r *http.Request) {
+	tree, ok := h.socTree(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree.MajorGroups())
+}
+
+// GetGroup returns one node of the SOC hierarchy. The code may be a full SOC
+// code at any level or a two-digit major group, e.g. "11" or "11-1000".
+func (h *SocHandler) GetGroup(w http.ResponseWriter, r *http.Request) {
+	tree, group, ok := h.socGroup(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"group":    group,
+		"children": tree.Children(group.Code),
+	})
+}
+
+// GetChildren lists the groups directly beneath a SOC group. Detailed SOC
+// codes have no child groups; use /soc/{socId} for their occupations.
+func (h *SocHandler) GetChildren(w http.ResponseWriter, r *http.Request) {
+	tree, group, ok := h.socGroup(w, r)
+	if !ok {
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(tree.Children(group.Code))
+}
+
+func (h *SocHandler) socTree(w http.ResponseWriter, r *http.Request) (*models.SocTree, bool) {
+	codes, err := h.repo.GetSocCodes(r.Context())
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve SOC groups")
+		return nil, false
+	}
+	return models.NewSocTree(codes), true
+}
+
+func (h *SocHandler) socGroup(w http.ResponseWriter, r *http.Request) (*models.SocTree, *models.SocGroup, bool) {
+	vars := mux.Vars(r)
+
+	code, err := models.NormalizeSocCode(vars["code"])
+	if err != nil {
+		http.Error(w, "Invalid SOC code: must look like '11' or '11-1000'", http.StatusBadRequest)
+		return nil, nil, false
+	}
+
+	tree, ok := h.socTree(w, r)
+	if !ok {
+		return nil, nil, false
+	}
+
+	group := tree.Group(code)
+	if group == nil {
+		http.Error(w, "SOC group not found", http.StatusNotFound)
+		return nil, nil, false
+	}
+
+	return tree, group, true
+}
@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+func (h *OccupationHandler) GetTasks(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	id := vars["id"]
+
+	limit, err := intParam(r, "limit", 20, 1, 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	offset, err := intParam(r, "offset", 0, 0, 10000)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	occ, err := h.repo.GetByID(r.Context(), id)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve occupation")
+		return
+	}
+	if occ == nil {
+		http.Error(w, "Occupation not found", http.StatusNotFound)
+		return
+	}
+
+	tasks, err := h.repo.GetTasks(r.Context(), id)
+	if err != nil {
+		repoError(w, r, err, "Failed to retrieve tasks")
+		return
+	}
+
+	total := len(tasks)
+	start := min(offset, total)
+	page := tasks[start:min(start+limit, total)]
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"occupation_id": id,
+		"total":         total,
+		"limit":         limit,
+		"offset":        offset,
+		"tasks":         page,
+	})
+}
+
+func (h *SearchHandler) SearchTasks(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query().Get("q")
+
+	if query == "" {
+		http.Error(w, "Missing search query parameter 'q'", http.StatusBadRequest)
+		return
+	}
+
+	limit, err := intParam(r, "limit", 20, 1, 100)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.repo.SearchTasks(r.Context(), query, limit)
+	if err != nil {
+		repoError(w, r, err, "Task search failed")
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(results)
+}
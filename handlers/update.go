@@ -0,0 +1,84 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gorilla/mux"
+	"go-careers/models"
+	"go-careers/repository"
+)
+
+// Update replaces an occupation, with its tasks, competencies and other child
+// records, by the record in the request body (see CreateBatch for the
+// format). The body's id may be omitted but must otherwise match the URL.
+func (h *OccupationHandler) Update(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var rec models.OccupationRecord
+	if err := json.NewDecoder(r.Body).Decode(&rec); err != nil {
+		http.Error(w, fmt.Sprintf("Invalid JSON: %s", err.Error()), http.StatusBadRequest)
+		return
+	}
+	if rec.ID == "" {
+		rec.ID = id
+	} else if rec.ID != id {
+		http.Error(w, "Occupation id does not match the URL", http.StatusBadRequest)
+		return
+	}
+
+	if err := h.repo.Update(r.Context(), rec); err != nil {
+		writeUpdateError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(rec.Occupation())
+}
+
+// Patch applies a JSON Merge Patch to an occupation, e.g.
+// {"title": "Chief Executive Officers"}. A null member clears that field.
+func (h *OccupationHandler) Patch(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, "Failed to read request body", http.StatusBadRequest)
+		return
+	}
+
+	occ, err := h.repo.Patch(r.Context(), id, patch)
+	if err != nil {
+		writeUpdateError(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(occ)
+}
+
+// Delete removes an occupation and everything attached to it.
+func (h *OccupationHandler) Delete(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if err := h.repo.Delete(r.Context(), id); err != nil {
+		writeUpdateError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func writeUpdateError(w http.ResponseWriter, r *http.Request, err error) {
+	switch {
+	case errors.Is(err, repository.ErrNotFound):
+		http.Error(w, "Occupation not found", http.StatusNotFound)
+	case errors.Is(err, repository.ErrInvalidOccupation):
+		http.Error(w, fmt.Sprintf("Validation error: %s", err.Error()), http.StatusBadRequest)
+	default:
+		repoError(w, r, err, "Failed to update occupation")
+	}
+}
@@ -0,0 +1,276 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"go-careers/logging"
+	"go-careers/models"
+	"go-careers/repository"
+)
+
+const (
+	// chunkSize is how many occupations are written per batch
+	chunkSize = 250
+	// maxLineBytes caps a single line, the same as the request size limit
+	// of POST /occupations
+	maxLineBytes = 1 << 20
+	// maxFailures caps the failed lines kept on a job
+	maxFailures = 100
+	// jobRetention is how long finished jobs can still be polled
+	jobRetention = 24 * time.Hour
+)
+
+// Manager runs NDJSON occupation imports as they are uploaded and keeps
+// their progress so clients can poll it.
+type Manager struct {
+	repo repository.OccupationStore
+
+	mu   sync.Mutex
+	jobs map[string]*models.ImportJob
+
+	// running tracks jobs still writing, so shutdown can wait for them
+	running sync.WaitGroup
+}
+
+func NewManager(repo repository.OccupationStore) *Manager {
+	return &Manager{
+		repo: repo,
+		jobs: map[string]*models.ImportJob{},
+	}
+}
+
+// Start registers a queued import of size bytes, or of unknown size when
+// size is negative, and returns it. The upload itself is read by Run.
+func (m *Manager) Start(mode models.BatchMode, size int64) models.ImportJob {
+	job := &models.ImportJob{
+		ID:        newJobID(),
+		Status:    models.ImportQueued,
+		Mode:      mode,
+		Bytes:     max(size, 0),
+		Failures:  []models.ImportFailure{},
+		CreatedAt: time.Now().UTC(),
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.prune()
+	m.jobs[job.ID] = job
+	return copyJob(job)
+}
+
+// Wait blocks until every running job has finished or ctx is done, returning
+// ctx's error in the latter case. Jobs left running keep going and fail once
+// the store is closed.
+func (m *Manager) Wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		m.running.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Get returns the current state of a job, or false if it is unknown or has
+// expired.
+func (m *Manager) Get(id string) (models.ImportJob, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	job, ok := m.jobs[id]
+	if !ok {
+		return models.ImportJob{}, false
+	}
+	return copyJob(job), true
+}
+
+// Run imports the job started with id from body, one occupation per line,
+// writing each chunk as soon as it has been read so progress is reported
+// while the upload is still arriving. Lines are numbered from 1, counting
+// blank lines; a line longer than maxLineBytes is skipped and reported as a
+// failure. It returns once body is exhausted or the job has failed.
+func (m *Manager) Run(ctx context.Context, id string, body io.Reader) {
+	m.mu.Lock()
+	job, ok := m.jobs[id]
+	m.mu.Unlock()
+	if !ok || job.Status != models.ImportQueued {
+		return
+	}
+
+	m.running.Add(1)
+	defer m.running.Done()
+
+	// Chunks already read are written even if the client goes away; each is
+	// still bounded by the store's write timeout, and the job keeps the
+	// request's values, such as the request id its logs are tagged with
+	ctx = context.WithoutCancel(ctx)
+
+	m.update(job, func() {
+		now := time.Now().UTC()
+		job.Status = models.ImportRunning
+		job.StartedAt = &now
+	})
+
+	var chunk []models.OccupationRecord
+	var chunkLines []int
+	flush := func() error {
+		if len(chunk) == 0 {
+			return nil
+		}
+		// An aborted chunk still reports the items written before the error
+		results, err := m.repo.CreateBatch(ctx, chunk, repository.BatchOptions{Mode: job.Mode})
+		m.update(job, func() {
+			for _, result := range results {
+				switch result.Status {
+				case models.BatchCreated:
+					job.Created++
+				case models.BatchUpdated:
+					job.Updated++
+				case models.BatchSkipped:
+					job.Skipped++
+				case models.BatchFailed:
+					addFailure(job, chunkLines[result.Index], result.ID, result.Reason)
+				}
+			}
+		})
+		if err != nil {
+			return err
+		}
+		chunk, chunkLines = chunk[:0], chunkLines[:0]
+		return nil
+	}
+
+	reader := bufio.NewReaderSize(body, maxLineBytes)
+	line := 0
+	var bytesRead int64
+	for {
+		b, readErr := reader.ReadSlice('\n')
+		bytesRead += int64(len(b))
+
+		if readErr == bufio.ErrBufferFull {
+			n, err := discardLine(reader)
+			bytesRead += n
+			line++
+			m.update(job, func() {
+				addFailure(job, line, "", fmt.Sprintf("line exceeds %d bytes", maxLineBytes))
+			})
+			b, readErr = nil, err
+		} else if len(b) > 0 {
+			line++
+		}
+		if readErr != nil && readErr != io.EOF {
+			m.fail(ctx, job, "Upload failed: "+readErr.Error(), readErr)
+			return
+		}
+
+		if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 {
+			var record models.OccupationRecord
+			if err := json.Unmarshal(trimmed, &record); err != nil {
+				m.update(job, func() {
+					addFailure(job, line, "", fmt.Sprintf("invalid JSON: %s", err))
+				})
+			} else {
+				chunk = append(chunk, record)
+				chunkLines = append(chunkLines, line)
+			}
+		}
+
+		if len(chunk) == chunkSize || readErr == io.EOF {
+			if err := flush(); err != nil {
+				m.fail(ctx, job, "Import failed", err)
+				return
+			}
+		}
+		m.update(job, func() {
+			job.Lines = line
+			job.BytesRead = bytesRead
+			if job.Bytes > 0 {
+				job.Progress = min(float64(bytesRead)/float64(job.Bytes), 1)
+			}
+		})
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	m.update(job, func() {
+		now := time.Now().UTC()
+		job.Status = models.ImportCompleted
+		job.Bytes = bytesRead
+		job.Progress = 1
+		job.FinishedAt = &now
+	})
+}
+
+// discardLine skips the rest of a line too long for reader's buffer,
+// returning how many bytes it skipped and nil once past the newline.
+func discardLine(reader *bufio.Reader) (int64, error) {
+	var n int64
+	for {
+		b, err := reader.ReadSlice('\n')
+		n += int64(len(b))
+		if err != bufio.ErrBufferFull {
+			return n, err
+		}
+	}
+}
+
+func (m *Manager) update(job *models.ImportJob, fn func()) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	fn()
+}
+
+// fail ends job with message, logging the underlying err.
+func (m *Manager) fail(ctx context.Context, job *models.ImportJob, message string, err error) {
+	logging.Logger(ctx).Error("Import failed", "import_id", job.ID, "error", err)
+	m.update(job, func() {
+		now := time.Now().UTC()
+		job.Status = models.ImportFailed
+		job.Error = message
+		job.FinishedAt = &now
+	})
+}
+
+// prune drops finished jobs older than jobRetention. m.mu must be held.
+func (m *Manager) prune() {
+	for id, job := range m.jobs {
+		if job.FinishedAt != nil && time.Since(*job.FinishedAt) > jobRetention {
+			delete(m.jobs, id)
+		}
+	}
+}
+
+func addFailure(job *models.ImportJob, line int, id, reason string) {
+	job.Failed++
+	if len(job.Failures) < maxFailures {
+		job.Failures = append(job.Failures, models.ImportFailure{Line: line, ID: id, Reason: reason})
+	}
+}
+
+func copyJob(job *models.ImportJob) models.ImportJob {
+	c := *job
+	c.Failures = append([]models.ImportFailure{}, job.Failures...)
+	return c
+}
+
+func newJobID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
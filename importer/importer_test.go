@@ -0,0 +1,116 @@
+package importer
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"go-careers/models"
+	"go-careers/repository"
+)
+
+func newTestManager(t *testing.T) *Manager {
+	t.Helper()
+	store, err := repository.NewMemoryStore("../seed_data/occupations.jsonl", "")
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	return NewManager(store)
+}
+
+func occupationLine(n int) string {
+	return fmt.Sprintf(`{"id":"99-%04d.00","socId":"99-%04d","socTitle":"T","title":"Title %d","singularTitle":"Title %d","description":"D"}`, n, n, n, n) + "\n"
+}
+
+func TestRunReportsEachLine(t *testing.T) {
+	m := newTestManager(t)
+
+	body := occupationLine(1) +
+		"\n" +
+		"{not json\n" +
+		`{"id":"99-0002.00","description":"` + strings.Repeat("x", maxLineBytes) + `"}` + "\n" +
+		`{"id":"11-1011.00","socId":"11-1011","socTitle":"T","title":"T","singularTitle":"T","description":"D"}` + "\n" +
+		occupationLine(3)
+
+	job := m.Start(models.BatchInsert, int64(len(body)))
+	if job.Status != models.ImportQueued {
+		t.Fatalf("Start status = %s, want queued", job.Status)
+	}
+	m.Run(context.Background(), job.ID, strings.NewReader(body))
+
+	job, _ = m.Get(job.ID)
+	if job.Status != models.ImportCompleted || job.Lines != 6 || job.Created != 2 || job.Failed != 3 || job.Progress != 1 || job.BytesRead != int64(len(body)) {
+		t.Fatalf("job = %+v", job)
+	}
+
+	wantLines := []int{3, 4, 5}
+	for i, f := range job.Failures {
+		if f.Line != wantLines[i] {
+			t.Errorf("failure %d on line %d, want %d: %s", i, f.Line, wantLines[i], f.Reason)
+		}
+	}
+	if reason := job.Failures[1].Reason; !strings.Contains(reason, "exceeds") {
+		t.Errorf("overlong line reason = %q", reason)
+	}
+}
+
+func TestRunWritesWhileUploading(t *testing.T) {
+	m := newTestManager(t)
+	job := m.Start(models.BatchInsert, -1)
+
+	r, w := io.Pipe()
+	done := make(chan struct{})
+	go func() {
+		m.Run(context.Background(), job.ID, r)
+		close(done)
+	}()
+
+	for i := 1; i <= chunkSize; i++ {
+		io.WriteString(w, occupationLine(i))
+	}
+
+	// The first chunk is written before the upload ends
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		job, _ = m.Get(job.ID)
+		if job.Created == chunkSize {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("first chunk not written while uploading: %+v", job)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if job.Status != models.ImportRunning || job.Bytes != 0 {
+		t.Errorf("mid-upload job = %+v, want running with unknown size", job)
+	}
+
+	io.WriteString(w, occupationLine(chunkSize+1))
+	w.Close()
+	<-done
+
+	job, _ = m.Get(job.ID)
+	if job.Status != models.ImportCompleted || job.Created != chunkSize+1 || job.Bytes != job.BytesRead {
+		t.Errorf("job = %+v", job)
+	}
+}
+
+func TestRunFailsOnReadError(t *testing.T) {
+	m := newTestManager(t)
+	job := m.Start(models.BatchInsert, -1)
+
+	r, w := io.Pipe()
+	go func() {
+		io.WriteString(w, occupationLine(1))
+		w.CloseWithError(fmt.Errorf("connection reset"))
+	}()
+	m.Run(context.Background(), job.ID, r)
+
+	job, _ = m.Get(job.ID)
+	if job.Status != models.ImportFailed || !strings.Contains(job.Error, "connection reset") {
+		t.Errorf("job = %+v, want failed with the read error", job)
+	}
+}
@@ -0,0 +1,87 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+type requestKey struct{}
+
+// Request is what is known about the request being served, gathered as it
+// passes through the middleware and repository for the access log.
+type Request struct {
+	ID string
+
+	mu           sync.Mutex
+	route        string
+	cacheLookups int
+	cacheHits    int
+}
+
+// WithRequest returns a context carrying req.
+func WithRequest(ctx context.Context, req *Request) context.Context {
+	return context.WithValue(ctx, requestKey{}, req)
+}
+
+// FromContext returns the request carried by ctx, or nil outside a request.
+func FromContext(ctx context.Context) *Request {
+	req, _ := ctx.Value(requestKey{}).(*Request)
+	return req
+}
+
+// RequestID returns the id of the request carried by ctx, or "" outside a
+// request.
+func RequestID(ctx context.Context) string {
+	if req := FromContext(ctx); req != nil {
+		return req.ID
+	}
+	return ""
+}
+
+// Logger returns the default logger, tagged with the request id of ctx when
+// there is one.
+func Logger(ctx context.Context) *slog.Logger {
+	if id := RequestID(ctx); id != "" {
+		return slog.Default().With("request_id", id)
+	}
+	return slog.Default()
+}
+
+// SetRoute records the route template the request matched.
+func SetRoute(ctx context.Context, route string) {
+	if req := FromContext(ctx); req != nil {
+		req.mu.Lock()
+		req.route = route
+		req.mu.Unlock()
+	}
+}
+
+// Route returns the route template recorded by SetRoute, or "" if the
+// request matched no route.
+func (r *Request) Route() string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.route
+}
+
+// RecordCacheLookup counts a cache lookup made on behalf of the request in
+// ctx. It does nothing outside a request.
+func RecordCacheLookup(ctx context.Context, hit bool) {
+	if req := FromContext(ctx); req != nil {
+		req.mu.Lock()
+		req.cacheLookups++
+		if hit {
+			req.cacheHits++
+		}
+		req.mu.Unlock()
+	}
+}
+
+// CacheHit reports whether every cache lookup of the request hit. ok is
+// false when the request made no lookups.
+func (r *Request) CacheHit() (hit, ok bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.cacheLookups > 0 && r.cacheHits == r.cacheLookups, r.cacheLookups > 0
+}
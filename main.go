@@ -1,17 +1,25 @@
 package main
 
 import (
+	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"go-careers/cache"
 	"go-careers/handlers"
+	"go-careers/importer"
+	"go-careers/metrics"
 	"go-careers/middleware"
 	"go-careers/repository"
 )
@@ -28,17 +36,26 @@ func initDB() *sql.DB {
 
 	db, err := sql.Open("mysql", dsn)
 	if err != nil {
-		log.Fatal("Error connecting to database:", err)
+		fatal("Error connecting to database", "error", err)
 	}
 
 	if err = db.Ping(); err != nil {
-		log.Fatal("Error pinging database:", err)
+		fatal("Error pinging database", "error", err)
 	}
 
-	log.Println("Connected to database successfully")
+	slog.Info("Connected to database successfully")
 	return db
 }
 
+// defaultTrustedProxies are the loopback and private networks.
+const defaultTrustedProxies = "127.0.0.0/8,::1,10.0.0.0/8,172.16.0.0/12,192.168.0.0/16,fc00::/7"
+
+// fatal logs msg as an error and exits. It is only for startup failures.
+func fatal(msg string, args ...any) {
+	slog.Error(msg, args...)
+	os.Exit(1)
+}
+
 func getEnv(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
@@ -46,60 +63,248 @@ func getEnv(key, fallback string) string {
 	return fallback
 }
 
-func healthCheck(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+// getEnvDuration reads a duration such as "5s" or "250ms" from the
+// environment.
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		fatal("Invalid duration", "key", key, "value", value, "error", err)
+	}
+	return d
 }
 
+// registerMetrics exposes the storage and rate limiter statistics. db and
+// redisCache may be nil.
+func registerMetrics(registry prometheus.Registerer, db *sql.DB, redisCache *cache.RedisCache, rateLimiter *middleware.RateLimiter) {
+	counter := func(name, help string, value func() float64) prometheus.Collector {
+		return prometheus.NewCounterFunc(prometheus.CounterOpts{Name: name, Help: help}, value)
+	}
+
+	if db != nil {
+		metrics.RegisterDBStats(registry, db)
+	}
+	if redisCache != nil {
+		registry.MustRegister(
+			counter("cache_hits_total", "Total cache lookups that found a value.", func() float64 {
+				return float64(redisCache.Stats().Hits)
+			}),
+			counter("cache_misses_total", "Total cache lookups that found nothing.", func() float64 {
+				return float64(redisCache.Stats().Misses)
+			}),
+			counter("cache_errors_total", "Total failed cache operations.", func() float64 {
+				return float64(redisCache.Stats().Errors)
+			}),
+		)
+	}
+	registry.MustRegister(counter("rate_limit_rejections_total", "Total requests refused by the rate limiter.", func() float64 {
+		return float64(rateLimiter.Rejected())
+	}))
+}
+
+// initMemoryStore loads the in-memory store from the JSONL export.
+func initMemoryStore() *repository.MemoryStore {
+	occupationsFile := getEnv("OCCUPATIONS_FILE", "seed_data/occupations.jsonl")
+	clustersFile := getEnv("CLUSTERS_FILE", "seed_data/career_clusters.json")
+
+	store, err := repository.NewMemoryStore(occupationsFile, clustersFile)
+	if err != nil {
+		fatal("Error loading occupations", "error", err)
+	}
+
+	slog.Info("Loaded occupations into memory", "file", occupationsFile)
+	return store
+}
 
 func main() {
-	db := initDB()
-	defer db.Close()
+	// Log as JSON; the standard logger, used by some libraries, goes
+	// through the same handler
+	slog.SetDefault(slog.New(slog.NewJSONHandler(os.Stdout, nil)))
 
-	// Initialize Redis cache (optional - gracefully degrades if unavailable)
+	// Select the storage backend: MySQL (default) or in-memory from JSONL
+	var occupationRepo repository.OccupationStore
+	var db *sql.DB
 	var redisCache *cache.RedisCache
-	redisHost := getEnv("REDIS_HOST", "")
-	redisPort := getEnv("REDIS_PORT", "6379")
-
-	if redisHost != "" {
-		var err error
-		redisCache, err = cache.NewRedisCache(redisHost, redisPort)
-		if err != nil {
-			log.Printf("Warning: Failed to connect to Redis: %v. Continuing without cache.", err)
-			redisCache = nil
+	var checks []handlers.Check
+	switch storage := getEnv("STORAGE", "mysql"); storage {
+	case "memory":
+		occupationRepo = initMemoryStore()
+	case "mysql":
+		db = initDB()
+		checks = append(checks, handlers.Check{Name: "database", Required: true, Ping: db.PingContext})
+
+		// Initialize Redis cache (optional - gracefully degrades if unavailable)
+		redisHost := getEnv("REDIS_HOST", "")
+		redisPort := getEnv("REDIS_PORT", "6379")
+
+		if redisHost != "" {
+			var err error
+			redisCache, err = cache.NewRedisCache(redisHost, redisPort, getEnvDuration("CACHE_TIMEOUT", 250*time.Millisecond))
+			if err != nil {
+				slog.Warn("Failed to connect to Redis. Continuing without cache.", "error", err)
+				redisCache = nil
+				// The cache is not retried, so readiness stays degraded
+				checks = append(checks, handlers.Check{Name: "cache", Ping: func(context.Context) error {
+					return errors.New("not connected at startup")
+				}})
+			} else {
+				slog.Info("Connected to Redis cache successfully")
+				checks = append(checks, handlers.Check{Name: "cache", Ping: redisCache.Ping})
+			}
 		} else {
-			defer redisCache.Close()
-			log.Println("Connected to Redis cache successfully")
+			slog.Info("Redis not configured. Running without cache.")
 		}
-	} else {
-		log.Println("Redis not configured. Running without cache.")
-	}
 
-	// Initialize repository
-	occupationRepo := repository.NewOccupationRepository(db, redisCache)
+		// Initialize repository
+		occupationRepo = repository.NewOccupationRepository(db, redisCache, repository.Timeouts{
+			Read:  getEnvDuration("DB_READ_TIMEOUT", 5*time.Second),
+			Write: getEnvDuration("DB_WRITE_TIMEOUT", 30*time.Second),
+		})
+	default:
+		fatal("Unknown STORAGE: must be 'mysql' or 'memory'", "storage", storage)
+	}
 
 	// Initialize handlers
+	healthHandler := handlers.NewHealthHandler(getEnvDuration("HEALTH_TIMEOUT", time.Second), checks...)
 	occupationHandler := handlers.NewOccupationHandler(occupationRepo)
 	searchHandler := handlers.NewSearchHandler(occupationRepo)
 	createHandler := handlers.NewCreateCareersHandler(occupationRepo)
+	matchHandler := handlers.NewMatchHandler(occupationRepo)
+	militaryHandler := handlers.NewMilitaryHandler(occupationRepo)
+	clusterHandler := handlers.NewClusterHandler(occupationRepo)
+	socHandler := handlers.NewSocHandler(occupationRepo)
+	imports := importer.NewManager(occupationRepo)
+	importHandler := handlers.NewImportHandler(imports)
 
 	// Setup routes
+	registry := prometheus.NewRegistry()
 	r := mux.NewRouter()
-	r.HandleFunc("/health", healthCheck).Methods("GET")
+	r.Use(middleware.RecordRoute)
+	r.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{})).Methods("GET")
+	r.HandleFunc("/livez", healthHandler.Livez).Methods("GET")
+	r.HandleFunc("/readyz", healthHandler.Readyz).Methods("GET")
+	r.HandleFunc("/health", healthHandler.Readyz).Methods("GET")
 	r.HandleFunc("/search", searchHandler.Search).Methods("GET")
+	r.HandleFunc("/tasks/search", searchHandler.SearchTasks).Methods("GET")
+	r.HandleFunc("/autocomplete", searchHandler.Autocomplete).Methods("GET")
 	r.HandleFunc("/occupations", occupationHandler.GetAll).Methods("GET")
 	r.HandleFunc("/occupations", createHandler.CreateBatch).Methods("POST")
+	r.HandleFunc("/occupations/import", importHandler.Import).Methods("POST")
+	r.HandleFunc("/imports/{id}", importHandler.GetImport).Methods("GET")
+	r.HandleFunc("/occupations/by-slug/{slug}", occupationHandler.GetBySlug).Methods("GET")
 	r.HandleFunc("/occupations/{id}", occupationHandler.GetByID).Methods("GET")
+	r.HandleFunc("/occupations/{id}", occupationHandler.Update).Methods("PUT")
+	r.HandleFunc("/occupations/{id}", occupationHandler.Patch).Methods("PATCH")
+	r.HandleFunc("/occupations/{id}", occupationHandler.Delete).Methods("DELETE")
 	r.HandleFunc("/occupations/{id}/similar", occupationHandler.GetSimilar).Methods("GET")
+	r.HandleFunc("/occupations/{id}/skills", occupationHandler.GetSkills).Methods("GET")
+	r.HandleFunc("/occupations/{id}/knowledge", occupationHandler.GetKnowledge).Methods("GET")
+	r.HandleFunc("/occupations/{id}/abilities", occupationHandler.GetAbilities).Methods("GET")
+	r.HandleFunc("/occupations/{id}/tasks", occupationHandler.GetTasks).Methods("GET")
+	r.HandleFunc("/occupations/{id}/military", occupationHandler.GetMilitaryCodes).Methods("GET")
+	r.HandleFunc("/occupations/{from}/gap/{to}", occupationHandler.GetGap).Methods("GET")
+	r.HandleFunc("/military/{moc}/occupations", militaryHandler.GetOccupations).Methods("GET")
+	r.HandleFunc("/soc/groups", socHandler.GetMajorGroups).Methods("GET")
+	r.HandleFunc("/soc/groups/{code}", socHandler.GetGroup).Methods("GET")
+	r.HandleFunc("/soc/groups/{code}/children", socHandler.GetChildren).Methods("GET")
+	r.HandleFunc("/soc/{socId}", occupationHandler.GetBySocID).Methods("GET")
+	r.HandleFunc("/clusters", clusterHandler.GetClusters).Methods("GET")
+	r.HandleFunc("/clusters/{id}/pathways", clusterHandler.GetPathways).Methods("GET")
+	r.HandleFunc("/pathways/{id}/occupations", clusterHandler.GetPathwayOccupations).Methods("GET")
+	r.HandleFunc("/match/interests", matchHandler.MatchInterests).Methods("POST")
 
-	// Apply security middleware
+	// Apply security middleware. Forwarding headers are believed only from
+	// TRUSTED_PROXIES, by default loopback and private networks such as the
+	// compose network nginx runs on; set it empty to trust none
+	trustedList, ok := os.LookupEnv("TRUSTED_PROXIES")
+	if !ok {
+		trustedList = defaultTrustedProxies
+	}
+	trustedProxies, err := middleware.ParseTrustedProxies(trustedList)
+	if err != nil {
+		fatal("Invalid TRUSTED_PROXIES", "error", err)
+	}
 	rateLimiter := middleware.NewRateLimiter(100) // 100 requests per minute
+	registerMetrics(registry, db, redisCache, rateLimiter)
 	handler := middleware.CORS(r)
 	handler = middleware.SecurityHeaders(handler)
-	handler = middleware.RequestSizeLimit(1048576)(handler) // 1MB limit
+	handler = middleware.RequestSizeLimit(1048576, "/occupations/import")(handler) // 1MB limit; imports set their own
 	handler = rateLimiter.Limit(handler)
+	handler = middleware.NewHTTPMetrics(registry).Instrument(handler)
+	handler = middleware.AccessLog(trustedProxies)(handler)
+	handler = middleware.RequestID(handler)
+
+	server := &http.Server{
+		Addr:              ":" + getEnv("PORT", "5000"),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+		ReadTimeout:       getEnvDuration("HTTP_READ_TIMEOUT", 30*time.Second),
+		WriteTimeout:      getEnvDuration("HTTP_WRITE_TIMEOUT", 60*time.Second),
+		IdleTimeout:       getEnvDuration("HTTP_IDLE_TIMEOUT", 120*time.Second),
+		ErrorLog:          slog.NewLogLogger(slog.Default().Handler(), slog.LevelWarn),
+	}
+	shutdownDelay := getEnvDuration("SHUTDOWN_DELAY", 0)
+	shutdownTimeout := getEnvDuration("SHUTDOWN_TIMEOUT", 20*time.Second)
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("Server starting", "addr", server.Addr)
+		serverErr <- server.ListenAndServe()
+	}()
+	healthHandler.SetReady(true)
+
+	stop := make(chan os.Signal, 1)
+	signal.Notify(stop, syscall.SIGTERM, os.Interrupt)
+
+	exitCode := 0
+	select {
+	case err := <-serverErr:
+		slog.Error("Server error", "error", err)
+		exitCode = 1
+	case sig := <-stop:
+		slog.Info("Shutting down", "signal", sig.String())
+		exitCode = shutdown(server, healthHandler, imports, shutdownDelay, shutdownTimeout)
+	}
+
+	// Close storage only once nothing can use it, the database before the
+	// cache it fronts
+	if db != nil {
+		if err := db.Close(); err != nil {
+			slog.Error("Error closing database", "error", err)
+		}
+	}
+	if redisCache != nil {
+		if err := redisCache.Close(); err != nil {
+			slog.Error("Error closing Redis", "error", err)
+		}
+	}
+
+	slog.Info("Server stopped")
+	os.Exit(exitCode)
+}
+
+// shutdown marks the server unready, waits delay for load balancers to
+// notice, then stops accepting connections and drains in-flight requests
+// and running imports within timeout. It returns the process exit code.
+func shutdown(server *http.Server, health *handlers.HealthHandler, imports *importer.Manager, delay, timeout time.Duration) int {
+	health.SetReady(false)
+	time.Sleep(delay)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
 
-	port := getEnv("PORT", "5000")
-	log.Printf("Server starting on port %s", port)
-	log.Fatal(http.ListenAndServe(":"+port, handler))
+	exitCode := 0
+	if err := server.Shutdown(ctx); err != nil {
+		slog.Error("Error draining connections", "error", err)
+		server.Close()
+		exitCode = 1
+	}
+	if err := imports.Wait(ctx); err != nil {
+		slog.Error("Imports still running at shutdown", "error", err)
+		exitCode = 1
+	}
+	return exitCode
 }
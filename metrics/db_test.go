@@ -0,0 +1,39 @@
+package metrics
+
+import (
+	"database/sql"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func TestRegisterDBStats(t *testing.T) {
+	// Opening does not connect, and the statistics need no connection
+	db, err := sql.Open("mysql", "user:password@tcp(127.0.0.1:1)/none")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(7)
+
+	registry := prometheus.NewRegistry()
+	RegisterDBStats(registry, db)
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		"# TYPE db_max_open_connections gauge\ndb_max_open_connections 7\n",
+		"# TYPE db_open_connections gauge\ndb_open_connections 0\n",
+		"# TYPE db_wait_count_total counter\ndb_wait_count_total 0\n",
+		"# TYPE db_max_lifetime_closed_total counter\ndb_max_lifetime_closed_total 0\n",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics missing %q in\n%s", want, body)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package middleware
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"go-careers/logging"
+)
+
+// RecordRoute notes the route template the request matched for AccessLog.
+// Install it with Router.Use.
+func RecordRoute(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		logging.SetRoute(r.Context(), routeTemplate(r))
+		next.ServeHTTP(w, r)
+	})
+}
+
+// AccessLog writes a structured log line for each request once it has been
+// served, taking the client address from the forwarding headers of requests
+// that come from one of proxies. It must run inside RequestID, and outside
+// the rate limiter so rejected requests are logged too.
+func AccessLog(proxies TrustedProxies) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return accessLog(proxies, next)
+	}
+}
+
+func accessLog(proxies TrustedProxies, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		attrs := []any{
+			"method", r.Method,
+			"route", requestRoute(r),
+			"path", r.URL.Path,
+			"status", rec.Status(),
+			"bytes", rec.bytes,
+			"duration_ms", float64(time.Since(start).Microseconds()) / 1000,
+			"client_ip", proxies.ClientIP(r),
+		}
+		if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
+			attrs = append(attrs, "forwarded_for", forwarded)
+		}
+		if req := logging.FromContext(r.Context()); req != nil {
+			if hit, ok := req.CacheHit(); ok {
+				attrs = append(attrs, "cache_hit", hit)
+			}
+		}
+		logging.Logger(r.Context()).Info("request", attrs...)
+	})
+}
+
+// TrustedProxies are the networks of the reverse proxies, such as nginx,
+// whose X-Forwarded-For and X-Real-IP headers name the client.
+type TrustedProxies []*net.IPNet
+
+// ParseTrustedProxies parses a comma-separated list of IP addresses and CIDR
+// networks, e.g. "10.0.0.0/8,127.0.0.1".
+func ParseTrustedProxies(list string) (TrustedProxies, error) {
+	var proxies TrustedProxies
+	for _, entry := range strings.Split(list, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		// A bare address is a network of one
+		cidr := entry
+		if !strings.Contains(cidr, "/") {
+			if strings.Contains(cidr, ":") {
+				cidr += "/128"
+			} else {
+				cidr += "/32"
+			}
+		}
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid trusted proxy: %s", entry)
+		}
+		proxies = append(proxies, network)
+	}
+	return proxies, nil
+}
+
+func (p TrustedProxies) trusts(ip net.IP) bool {
+	for _, network := range p {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ClientIP returns the address of the client that sent r. That is the
+// connection's peer, unless the peer is a trusted proxy: then it is the
+// nearest untrusted address in X-Forwarded-For, read from the right since
+// clients can prepend their own, or the furthest one when every hop is
+// trusted. Without X-Forwarded-For it is X-Real-IP.
+func (p TrustedProxies) ClientIP(r *http.Request) string {
+	peer, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		peer = r.RemoteAddr
+	}
+	if ip := net.ParseIP(peer); ip == nil || !p.trusts(ip) {
+		return peer
+	}
+
+	var nearest net.IP
+	hops := strings.Split(strings.Join(r.Header.Values("X-Forwarded-For"), ","), ",")
+	for i := len(hops) - 1; i >= 0; i-- {
+		ip := net.ParseIP(strings.TrimSpace(hops[i]))
+		if ip == nil {
+			break
+		}
+		if !p.trusts(ip) {
+			return ip.String()
+		}
+		nearest = ip
+	}
+	if nearest != nil {
+		return nearest.String()
+	}
+
+	if ip := net.ParseIP(strings.TrimSpace(r.Header.Get("X-Real-IP"))); ip != nil {
+		return ip.String()
+	}
+	return peer
+}
@@ -0,0 +1,66 @@
+package middleware
+
+import (
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClientIP(t *testing.T) {
+	proxies, err := ParseTrustedProxies("172.16.0.0/12, 127.0.0.1,::1")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		peer      string
+		forwarded []string
+		realIP    string
+		want      string
+	}{
+		{"direct client", "203.0.113.7:4000", nil, "", "203.0.113.7"},
+		{"untrusted peer cannot spoof", "203.0.113.7:4000", []string{"198.51.100.1"}, "198.51.100.1", "203.0.113.7"},
+		{"through nginx", "172.18.0.5:4000", []string{"198.51.100.1"}, "198.51.100.1", "198.51.100.1"},
+		{"prepended hop ignored", "172.18.0.5:4000", []string{"10.9.9.9, 198.51.100.1"}, "", "198.51.100.1"},
+		{"trusted hops skipped", "127.0.0.1:4000", []string{"198.51.100.1, 172.18.0.9"}, "", "198.51.100.1"},
+		{"repeated headers", "[::1]:4000", []string{"192.0.2.1", "198.51.100.1"}, "", "198.51.100.1"},
+		{"every hop trusted", "172.18.0.5:4000", []string{"172.18.0.2, 172.18.0.3"}, "", "172.18.0.2"},
+		{"garbage hop stops the walk", "172.18.0.5:4000", []string{"198.51.100.1, nonsense, 172.18.0.3"}, "", "172.18.0.3"},
+		{"X-Real-IP alone", "172.18.0.5:4000", nil, "198.51.100.1", "198.51.100.1"},
+		{"no headers", "172.18.0.5:4000", nil, "", "172.18.0.5"},
+		{"invalid X-Real-IP", "172.18.0.5:4000", nil, "unknown", "172.18.0.5"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest("GET", "/", nil)
+		r.RemoteAddr = tt.peer
+		for _, v := range tt.forwarded {
+			r.Header.Add("X-Forwarded-For", v)
+		}
+		if tt.realIP != "" {
+			r.Header.Set("X-Real-IP", tt.realIP)
+		}
+		if got := proxies.ClientIP(r); got != tt.want {
+			t.Errorf("%s: ClientIP = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestParseTrustedProxies(t *testing.T) {
+	tests := []struct {
+		list    string
+		want    int
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"10.0.0.0/8", 1, false},
+		{" 127.0.0.1 , ::1,fc00::/7", 3, false},
+		{"localhost", 0, true},
+		{"10.0.0.0/33", 0, true},
+	}
+	for _, tt := range tests {
+		proxies, err := ParseTrustedProxies(tt.list)
+		if (err != nil) != tt.wantErr || len(proxies) != tt.want {
+			t.Errorf("ParseTrustedProxies(%q) = %d networks, %v", tt.list, len(proxies), err)
+		}
+	}
+}
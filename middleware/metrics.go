@@ -0,0 +1,75 @@
+package middleware
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"go-careers/logging"
+)
+
+// unmatchedRoute labels requests that matched no route, including those
+// refused before routing, so scans of random paths do not create a series
+// each.
+const unmatchedRoute = "unmatched"
+
+// routeTemplate returns the template of the route r matched, or
+// unmatchedRoute.
+func routeTemplate(r *http.Request) string {
+	if current := mux.CurrentRoute(r); current != nil {
+		if tpl, err := current.GetPathTemplate(); err == nil {
+			return tpl
+		}
+	}
+	return unmatchedRoute
+}
+
+// requestRoute returns the route template RecordRoute noted for r once it
+// has been served, or unmatchedRoute.
+func requestRoute(r *http.Request) string {
+	if req := logging.FromContext(r.Context()); req != nil && req.Route() != "" {
+		return req.Route()
+	}
+	return unmatchedRoute
+}
+
+// HTTPMetrics counts requests and records their latency by method, route
+// template (such as /occupations/{id}) and status.
+type HTTPMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func NewHTTPMetrics(registry prometheus.Registerer) *HTTPMetrics {
+	m := &HTTPMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Name: "http_requests_total",
+			Help: "Total HTTP requests by method, route and status code.",
+		}, []string{"method", "route", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "http_request_duration_seconds",
+			Help:    "HTTP request latency by method and route.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"method", "route"}),
+	}
+	registry.MustRegister(m.requests, m.duration)
+	return m
+}
+
+// Instrument records each request served by next. It must run inside
+// RequestID and outside everything that can answer a request, such as the
+// rate limiter, so every response is counted; the route is the one
+// RecordRoute noted, or "unmatched".
+func (m *HTTPMetrics) Instrument(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rec, r)
+
+		route := requestRoute(r)
+		m.requests.WithLabelValues(r.Method, route, strconv.Itoa(rec.Status())).Inc()
+		m.duration.WithLabelValues(r.Method, route).Observe(time.Since(start).Seconds())
+	})
+}
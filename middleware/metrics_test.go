@@ -0,0 +1,54 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+func TestInstrumentCountsEveryResponse(t *testing.T) {
+	registry := prometheus.NewRegistry()
+	m := NewHTTPMetrics(registry)
+
+	r := mux.NewRouter()
+	r.Use(RecordRoute)
+	r.HandleFunc("/occupations/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}).Methods("GET")
+
+	// The same order as main: the rate limiter answers before routing
+	limiter := NewRateLimiter(5)
+	handler := RequestID(m.Instrument(limiter.Limit(r)))
+
+	for _, req := range []struct{ method, path string }{
+		{"GET", "/occupations/11-1011.00"},
+		{"GET", "/occupations/13-2051.00"},
+		{"DELETE", "/occupations/13-2051.00"},
+		{"GET", "/wp-login.php"},
+		{"GET", "/occupations/11-1011.00"},
+		{"GET", "/occupations/11-1011.00"},
+	} {
+		handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	promhttp.HandlerFor(registry, promhttp.HandlerOpts{}).ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body := rec.Body.String()
+	for _, want := range []string{
+		`http_requests_total{code="418",method="GET",route="/occupations/{id}"} 3`,
+		`http_requests_total{code="405",method="DELETE",route="unmatched"} 1`,
+		`http_requests_total{code="404",method="GET",route="unmatched"} 1`,
+		`http_requests_total{code="429",method="GET",route="unmatched"} 1`,
+		`http_request_duration_seconds_count{method="GET",route="/occupations/{id}"} 3`,
+		`http_request_duration_seconds_bucket{method="GET",route="unmatched",le="+Inf"} 2`,
+	} {
+		if !strings.Contains(body, want+"\n") {
+			t.Errorf("metrics missing %s in\n%s", want, body)
+		}
+	}
+}
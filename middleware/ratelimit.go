@@ -3,6 +3,7 @@ package middleware
 import (
 	"net/http"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -16,6 +17,7 @@ type RateLimiter struct {
 	mu       sync.RWMutex
 	limit    int
 	window   time.Duration
+	rejected atomic.Uint64
 }
 
 func NewRateLimiter(requestsPerMinute int) *RateLimiter {
@@ -44,6 +46,12 @@ func (rl *RateLimiter) cleanup() {
 	}
 }
 
+// Rejected returns how many requests have been refused for exceeding the
+// limit.
+func (rl *RateLimiter) Rejected() uint64 {
+	return rl.rejected.Load()
+}
+
 func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		ip := r.RemoteAddr
@@ -71,6 +79,7 @@ func (rl *RateLimiter) Limit(next http.Handler) http.Handler {
 
 		if v.count >= rl.limit {
 			rl.mu.Unlock()
+			rl.rejected.Add(1)
 			http.Error(w, "Rate limit exceeded. Please try again later.", http.StatusTooManyRequests)
 			return
 		}
@@ -0,0 +1,55 @@
+package middleware
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/http"
+
+	"go-careers/logging"
+)
+
+// RequestIDHeader carries the id that correlates a request's log lines.
+const RequestIDHeader = "X-Request-ID"
+
+// maxRequestIDLength bounds a propagated request id.
+const maxRequestIDLength = 128
+
+// RequestID tags each request with the X-Request-ID it arrived with, such as
+// one set by a proxy, or a new one if it has none or an unusable one. The id
+// is echoed in the response and carried in the request context for
+// logging.Logger.
+func RequestID(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(RequestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+
+		w.Header().Set(RequestIDHeader, id)
+		ctx := logging.WithRequest(r.Context(), &logging.Request{ID: id})
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// validRequestID accepts ids such as UUIDs, keeping anything that could
+// forge log or header content out.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for _, c := range id {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		case c == '-', c == '_', c == '.', c == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
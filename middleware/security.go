@@ -4,10 +4,18 @@ import (
 	"net/http"
 )
 
-// RequestSizeLimit limits the size of request bodies to prevent DoS attacks
-func RequestSizeLimit(maxBytes int64) func(http.Handler) http.Handler {
+// RequestSizeLimit limits the size of request bodies to prevent DoS attacks.
+// Requests to the exempt paths are passed through unlimited; their handlers
+// must apply their own limit.
+func RequestSizeLimit(maxBytes int64, exempt ...string) func(http.Handler) http.Handler {
 	return func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			for _, path := range exempt {
+				if r.URL.Path == path {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
 			r.Body = http.MaxBytesReader(w, r.Body, maxBytes)
 			next.ServeHTTP(w, r)
 		})
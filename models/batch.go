@@ -0,0 +1,42 @@
+package models
+
+import "fmt"
+
+// BatchMode says how a batch create treats occupations that already exist.
+type BatchMode string
+
+const (
+	// BatchInsert fails items whose id already exists
+	BatchInsert BatchMode = "insert"
+	// BatchUpsert replaces existing occupations
+	BatchUpsert BatchMode = "upsert"
+	// BatchSkipExisting leaves existing occupations untouched
+	BatchSkipExisting BatchMode = "skip_existing"
+)
+
+// ParseBatchMode parses a mode query parameter, defaulting to BatchInsert.
+func ParseBatchMode(s string) (BatchMode, error) {
+	switch m := BatchMode(s); m {
+	case "":
+		return BatchInsert, nil
+	case BatchInsert, BatchUpsert, BatchSkipExisting:
+		return m, nil
+	}
+	return "", fmt.Errorf("invalid mode: must be 'insert', 'upsert' or 'skip_existing'")
+}
+
+// Outcomes of a single batch item.
+const (
+	BatchCreated = "created"
+	BatchUpdated = "updated"
+	BatchSkipped = "skipped"
+	BatchFailed  = "failed"
+)
+
+// BatchResult reports what happened to the item at Index of a batch.
+type BatchResult struct {
+	Index  int    `json:"index"`
+	ID     string `json:"id,omitempty"`
+	Status string `json:"status"`
+	Reason string `json:"reason,omitempty"`
+}
@@ -0,0 +1,31 @@
+package models
+
+// CareerCluster is a top-level career field (e.g. "Financial Services")
+// grouping related career pathways.
+type CareerCluster struct {
+	ID              int    `json:"id"`
+	Name            string `json:"name"`
+	PathwayCount    int    `json:"pathway_count"`
+	OccupationCount int    `json:"occupation_count"`
+}
+
+// CareerPathway is a specialisation within a career cluster. Pathway ids are
+// "<cluster>.<n>", e.g. "14.2".
+type CareerPathway struct {
+	ID              string `json:"id"`
+	ClusterID       int    `json:"cluster_id"`
+	Name            string `json:"name,omitempty"`
+	OccupationCount int    `json:"occupation_count"`
+}
+
+// ClusterNames is an entry of the career cluster and pathway names file,
+// seed_data/career_clusters.json, read by the seed converter and the memory
+// store.
+type ClusterNames struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	Pathways []struct {
+		ID   string `json:"id"`
+		Name string `json:"name"`
+	} `json:"pathways"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// CompetencyType identifies one of the O*NET competency collections stored
+// alongside each occupation.
+type CompetencyType string
+
+const (
+	CompetencySkills    CompetencyType = "skills"
+	CompetencyKnowledge CompetencyType = "knowledge"
+	CompetencyAbilities CompetencyType = "abilities"
+)
+
+type Competency struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Importance  float64 `json:"importance"`
+	Level       float64 `json:"level"`
+}
@@ -0,0 +1,131 @@
+package models
+
+import "strings"
+
+// EducationLevel is a normalized education attainment level. The O*NET data
+// uses free-text labels such as "a Bachelor's degree"; EducationLevel gives
+// them stable, ordered names.
+type EducationLevel string
+
+const (
+	EducationHighSchool  EducationLevel = "high_school"
+	EducationCertificate EducationLevel = "certificate"
+	EducationSomeCollege EducationLevel = "some_college"
+	EducationAssociate   EducationLevel = "associate"
+	EducationBachelor    EducationLevel = "bachelor"
+	EducationMaster      EducationLevel = "master"
+	EducationDoctorate   EducationLevel = "doctorate"
+)
+
+// EducationLevels lists every level from lowest to highest attainment.
+var EducationLevels = []EducationLevel{
+	EducationHighSchool,
+	EducationCertificate,
+	EducationSomeCollege,
+	EducationAssociate,
+	EducationBachelor,
+	EducationMaster,
+	EducationDoctorate,
+}
+
+// educationLabels maps each level to the label used in the O*NET data.
+var educationLabels = map[EducationLevel]string{
+	EducationHighSchool:  "a high school diploma or less",
+	EducationCertificate: "a certificate",
+	EducationSomeCollege: "some college",
+	EducationAssociate:   "an Associate degree",
+	EducationBachelor:    "a Bachelor's degree",
+	EducationMaster:      "a Master's or Professional degree",
+	EducationDoctorate:   "a Doctoral degree or more",
+}
+
+// EducationShare is the percentage of workers in an occupation whose highest
+// attainment is Level.
+type EducationShare struct {
+	Level   EducationLevel `json:"level"`
+	Label   string         `json:"label"`
+	Percent float64        `json:"percent"`
+}
+
+// NewEducationDistribution normalizes the attainment levels of the O*NET
+// export.
+func NewEducationDistribution(levels []AttainmentLevel) []EducationShare {
+	var shares []EducationShare
+	for _, l := range levels {
+		shares = append(shares, EducationShare{
+			Level:   EducationLevelFromLabel(l.Level),
+			Label:   l.Level,
+			Percent: l.Percent,
+		})
+	}
+	return shares
+}
+
+// ParseEducationLevel accepts a normalized level name such as "associate".
+func ParseEducationLevel(s string) (EducationLevel, bool) {
+	level := EducationLevel(strings.ToLower(s))
+	_, ok := educationLabels[level]
+	return level, ok
+}
+
+// EducationLevelFromLabel normalizes an O*NET education label, returning ""
+// for unknown or empty labels.
+func EducationLevelFromLabel(label string) EducationLevel {
+	for level, l := range educationLabels {
+		if strings.EqualFold(l, label) {
+			return level
+		}
+	}
+	return ""
+}
+
+// Label returns the O*NET label for the level.
+func (l EducationLevel) Label() string {
+	return educationLabels[l]
+}
+
+// Rank orders levels from 0 (high school) upwards, or -1 for unknown levels.
+func (l EducationLevel) Rank() int {
+	for i, level := range EducationLevels {
+		if level == l {
+			return i
+		}
+	}
+	return -1
+}
+
+// EducationFilter restricts occupations by their typical education level to
+// exactly Level, or to at most Max. Zero fields are ignored.
+type EducationFilter struct {
+	Level EducationLevel
+	Max   EducationLevel
+}
+
+func (f EducationFilter) IsZero() bool {
+	return f.Level == "" && f.Max == ""
+}
+
+// Matches reports whether an occupation with the given typical education
+// label passes the filter.
+func (f EducationFilter) Matches(label string) bool {
+	level := EducationLevelFromLabel(label)
+	if f.Level != "" && level != f.Level {
+		return false
+	}
+	if f.Max != "" && (level == "" || level.Rank() > f.Max.Rank()) {
+		return false
+	}
+	return true
+}
+
+// Labels returns the O*NET labels of every level passing the filter, for use
+// in SQL IN clauses.
+func (f EducationFilter) Labels() []string {
+	var labels []string
+	for _, level := range EducationLevels {
+		if f.Matches(level.Label()) {
+			labels = append(labels, level.Label())
+		}
+	}
+	return labels
+}
@@ -0,0 +1,96 @@
+package models
+
+import "sort"
+
+// CompetencyGap is a competency the target occupation needs at a higher level
+// than the source occupation has it. WeightedGap scales the level delta by the
+// target's importance (1-5) so critical competencies rank first.
+type CompetencyGap struct {
+	Name        string  `json:"name"`
+	FromLevel   float64 `json:"from_level"`
+	ToLevel     float64 `json:"to_level"`
+	Delta       float64 `json:"delta"`
+	Importance  float64 `json:"importance"`
+	WeightedGap float64 `json:"weighted_gap"`
+}
+
+// CompetencyGapReport compares one competency collection of two occupations.
+// Difficulty is the importance-weighted share of the target's required levels
+// that the source lacks, from 0 (nothing to learn) to 100 (starting from
+// scratch).
+type CompetencyGapReport struct {
+	Difficulty float64         `json:"difficulty"`
+	Gaps       []CompetencyGap `json:"gaps"`
+
+	missing  float64
+	required float64
+}
+
+// CareerGap is the full skill-gap analysis for moving between two
+// occupations. Difficulty combines all three competency collections.
+type CareerGap struct {
+	From       Occupation          `json:"from"`
+	To         Occupation          `json:"to"`
+	Difficulty float64             `json:"difficulty"`
+	Skills     CompetencyGapReport `json:"skills"`
+	Knowledge  CompetencyGapReport `json:"knowledge"`
+	Abilities  CompetencyGapReport `json:"abilities"`
+}
+
+// CompareCompetencies lists what must be raised to go from one occupation's
+// competencies to another's. Competencies absent from the source count as
+// level zero.
+func CompareCompetencies(from, to []Competency) CompetencyGapReport {
+	fromLevels := make(map[string]float64, len(from))
+	for _, c := range from {
+		fromLevels[c.Name] = c.Level
+	}
+
+	report := CompetencyGapReport{Gaps: []CompetencyGap{}}
+	for _, c := range to {
+		report.required += c.Level * c.Importance
+
+		delta := c.Level - fromLevels[c.Name]
+		if delta <= 0 {
+			continue
+		}
+		report.missing += delta * c.Importance
+		report.Gaps = append(report.Gaps, CompetencyGap{
+			Name:        c.Name,
+			FromLevel:   fromLevels[c.Name],
+			ToLevel:     c.Level,
+			Delta:       round3(delta),
+			Importance:  c.Importance,
+			WeightedGap: round3(delta * c.Importance / 5),
+		})
+	}
+
+	sort.SliceStable(report.Gaps, func(i, j int) bool {
+		return report.Gaps[i].WeightedGap > report.Gaps[j].WeightedGap
+	})
+	report.Difficulty = difficulty(report.missing, report.required)
+
+	return report
+}
+
+// NewCareerGap combines the per-collection reports into one analysis.
+func NewCareerGap(from, to Occupation, skills, knowledge, abilities CompetencyGapReport) CareerGap {
+	missing := skills.missing + knowledge.missing + abilities.missing
+	required := skills.required + knowledge.required + abilities.required
+
+	return CareerGap{
+		From:       from,
+		To:         to,
+		Difficulty: difficulty(missing, required),
+		Skills:     skills,
+		Knowledge:  knowledge,
+		Abilities:  abilities,
+	}
+}
+
+func difficulty(missing, required float64) float64 {
+	if required == 0 {
+		return 0
+	}
+	return round3(missing / required * 100)
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// Import job states.
+const (
+	ImportQueued    = "queued"
+	ImportRunning   = "running"
+	ImportCompleted = "completed"
+	ImportFailed    = "failed"
+)
+
+// ImportJob is the progress of a bulk occupation import. Bytes is the
+// upload's declared length, or 0 until it finishes when none was given;
+// Progress is the share of it processed so far, from 0 to 1. Error is set
+// when the whole job failed; failures of single lines are listed in
+// Failures, up to a limit, and counted in Failed.
+type ImportJob struct {
+	ID         string          `json:"id"`
+	Status     string          `json:"status"`
+	Mode       BatchMode       `json:"mode"`
+	Bytes      int64           `json:"bytes"`
+	BytesRead  int64           `json:"bytes_read"`
+	Progress   float64         `json:"progress"`
+	Lines      int             `json:"lines"`
+	Created    int             `json:"created"`
+	Updated    int             `json:"updated"`
+	Skipped    int             `json:"skipped"`
+	Failed     int             `json:"failed"`
+	Failures   []ImportFailure `json:"failures"`
+	Error      string          `json:"error,omitempty"`
+	CreatedAt  time.Time       `json:"created_at"`
+	StartedAt  *time.Time      `json:"started_at,omitempty"`
+	FinishedAt *time.Time      `json:"finished_at,omitempty"`
+}
+
+// ImportFailure is a line of an import that could not be written.
+type ImportFailure struct {
+	Line   int    `json:"line"`
+	ID     string `json:"id,omitempty"`
+	Reason string `json:"reason"`
+}
@@ -0,0 +1,104 @@
+package models
+
+import (
+	"fmt"
+	"math"
+)
+
+// InterestProfile holds scores for the six Holland (RIASEC) interest
+// dimensions. Any non-negative scale may be used; matching only compares the
+// shape of two profiles.
+type InterestProfile struct {
+	R float64 `json:"R"`
+	I float64 `json:"I"`
+	A float64 `json:"A"`
+	S float64 `json:"S"`
+	E float64 `json:"E"`
+	C float64 `json:"C"`
+}
+
+// InterestMatch is an occupation ranked against a user's interest profile.
+// Contributions splits Score across the six dimensions and sums to it.
+type InterestMatch struct {
+	Occupation
+	Score         float64         `json:"score"`
+	Profile       InterestProfile `json:"riasec"`
+	Contributions InterestProfile `json:"contributions"`
+}
+
+// Similarity methods accepted by InterestProfile.Similarity.
+const (
+	SimilarityCosine      = "cosine"
+	SimilarityCorrelation = "correlation"
+)
+
+func (p InterestProfile) values() [6]float64 {
+	return [6]float64{p.R, p.I, p.A, p.S, p.E, p.C}
+}
+
+func profileFromValues(v [6]float64) InterestProfile {
+	return InterestProfile{R: v[0], I: v[1], A: v[2], S: v[3], E: v[4], C: v[5]}
+}
+
+func (p InterestProfile) Validate() error {
+	total := 0.0
+	for _, v := range p.values() {
+		if v < 0 || math.IsNaN(v) || math.IsInf(v, 0) {
+			return fmt.Errorf("interest scores must be non-negative numbers")
+		}
+		total += v
+	}
+	if total == 0 {
+		return fmt.Errorf("at least one interest score must be greater than zero")
+	}
+	return nil
+}
+
+// Similarity compares two profiles with cosine similarity or Pearson
+// correlation and returns the score together with each dimension's share of
+// it. ok is false when the score is undefined, e.g. correlating a flat
+// profile whose six scores are all equal.
+func (p InterestProfile) Similarity(other InterestProfile, method string) (score float64, contributions InterestProfile, ok bool) {
+	a, b := p.values(), other.values()
+
+	if method == SimilarityCorrelation {
+		meanA, meanB := mean(a), mean(b)
+		for i := range a {
+			a[i] -= meanA
+			b[i] -= meanB
+		}
+	}
+
+	normA, normB := norm(a), norm(b)
+	if normA == 0 || normB == 0 {
+		return 0, InterestProfile{}, false
+	}
+
+	var parts [6]float64
+	for i := range a {
+		parts[i] = round3(a[i] * b[i] / (normA * normB))
+		score += a[i] * b[i] / (normA * normB)
+	}
+
+	return round3(score), profileFromValues(parts), true
+}
+
+func mean(v [6]float64) float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x
+	}
+	return sum / float64(len(v))
+}
+
+func norm(v [6]float64) float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+func round3(x float64) float64 {
+	return math.Round(x*1000) / 1000
+}
@@ -0,0 +1,58 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MilitaryCode is a decoded military occupation code (MOC) from the O*NET
+// crosswalk. Codes look like "N_O_111": branch letter, personnel category
+// letter, then the service's own occupation code.
+type MilitaryCode struct {
+	Code        string `json:"code"`
+	Branch      string `json:"branch"`
+	Category    string `json:"category"`
+	ServiceCode string `json:"service_code"`
+}
+
+// MilitaryUnknown is the branch or category of a code whose letter is not
+// decoded. The crosswalk also uses branch letters D, G, J, P, Q, S, V, X and
+// Y, about a fifth of its codes, whose services it does not document.
+const MilitaryUnknown = "unknown"
+
+// militaryBranches maps the crosswalk's branch letters to service names.
+var militaryBranches = map[string]string{
+	"A": "Army",
+	"C": "Coast Guard",
+	"F": "Air Force",
+	"M": "Marine Corps",
+	"N": "Navy",
+}
+
+var militaryCategories = map[string]string{
+	"E": "enlisted",
+	"O": "officer",
+	"W": "warrant_officer",
+}
+
+// ParseMOC decodes a crosswalk code such as "F_E_1P011".
+func ParseMOC(code string) (MilitaryCode, error) {
+	parts := strings.SplitN(code, "_", 3)
+	if len(parts) != 3 || len(parts[0]) != 1 || len(parts[1]) != 1 || parts[2] == "" {
+		return MilitaryCode{}, fmt.Errorf("invalid military occupation code: %s", code)
+	}
+
+	moc := MilitaryCode{
+		Code:        code,
+		Branch:      militaryBranches[parts[0]],
+		Category:    militaryCategories[parts[1]],
+		ServiceCode: parts[2],
+	}
+	if moc.Branch == "" {
+		moc.Branch = MilitaryUnknown
+	}
+	if moc.Category == "" {
+		moc.Category = MilitaryUnknown
+	}
+	return moc, nil
+}
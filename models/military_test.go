@@ -0,0 +1,26 @@
+package models
+
+import "testing"
+
+func TestParseMOC(t *testing.T) {
+	tests := []struct {
+		code    string
+		want    MilitaryCode
+		wantErr bool
+	}{
+		{code: "N_O_111", want: MilitaryCode{Code: "N_O_111", Branch: "Navy", Category: "officer", ServiceCode: "111"}},
+		{code: "F_E_1P011", want: MilitaryCode{Code: "F_E_1P011", Branch: "Air Force", Category: "enlisted", ServiceCode: "1P011"}},
+		{code: "A_W_890A", want: MilitaryCode{Code: "A_W_890A", Branch: "Army", Category: "warrant_officer", ServiceCode: "890A"}},
+		{code: "V_O_J1O", want: MilitaryCode{Code: "V_O_J1O", Branch: MilitaryUnknown, Category: "officer", ServiceCode: "J1O"}},
+		{code: "G_-_0308", want: MilitaryCode{Code: "G_-_0308", Branch: MilitaryUnknown, Category: MilitaryUnknown, ServiceCode: "0308"}},
+		{code: "N_O_", wantErr: true},
+		{code: "NO_111", wantErr: true},
+		{code: "N_OO_111", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := ParseMOC(tt.code)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("ParseMOC(%q) = %+v, %v", tt.code, got, err)
+		}
+	}
+}
@@ -10,6 +10,10 @@ type Occupation struct {
 	SingularTitle  string `json:"singular_title"`
 	Description    string `json:"description"`
 	TypicalEdLevel string `json:"typical_ed_level"`
+
+	// Only populated on the occupation detail
+	EducationLevel        EducationLevel   `json:"education_level,omitempty"`
+	EducationDistribution []EducationShare `json:"education_distribution,omitempty"`
 }
 
 func (o *Occupation) Validate() error {
@@ -0,0 +1,220 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// OccupationRecord is a complete occupation in the camelCase format of the
+// O*NET export, one per line of seed_data/occupations.jsonl. It is what the
+// write endpoints accept: the core Occupation fields plus the child
+// collections and similarity lists stored alongside them.
+type OccupationRecord struct {
+	ID               string `json:"id"`
+	SocID            string `json:"socId"`
+	SocTitle         string `json:"socTitle"`
+	Title            string `json:"title"`
+	SingularTitle    string `json:"singularTitle"`
+	HumanizedTitle   string `json:"humanizedTitle,omitempty"`
+	ShortTitle       string `json:"shortTitle,omitempty"`
+	PluralShortTitle string `json:"pluralShortTitle,omitempty"`
+	Description      string `json:"description"`
+	TitleSlug        string `json:"titleSlug,omitempty"`
+	TypicalEdLevel   string `json:"typicalEdLevel"`
+
+	Categories []int    `json:"categories,omitempty"`
+	Pathways   []string `json:"pathways,omitempty"`
+	Mocs       []string `json:"mocs,omitempty"`
+	LayTitles  []string `json:"layTitles,omitempty"`
+	EmsiTitles []string `json:"emsiTitles,omitempty"`
+	CoreTasks  []string `json:"coreTasks,omitempty"`
+
+	SimilarOccs                    []string `json:"similarOccs,omitempty"`
+	SimilarByCapabilitiesInterests []string `json:"similarByCapabilitiesInterests,omitempty"`
+	SimilarBySkillsExperience      []string `json:"similarBySkillsExperience,omitempty"`
+
+	Skills    []CompetencyRecord `json:"skills,omitempty"`
+	Knowledge []CompetencyRecord `json:"knowledge,omitempty"`
+	Abilities []CompetencyRecord `json:"abilities,omitempty"`
+
+	RiasecTraits              *InterestProfile  `json:"riasecTraits,omitempty"`
+	EducationAttainmentLevels []AttainmentLevel `json:"educationAttainmentLevels,omitempty"`
+}
+
+// CompetencyRecord is a skill, knowledge area or ability as exported by
+// O*NET.
+type CompetencyRecord struct {
+	Name        string    `json:"name"`
+	Description string    `json:"description"`
+	Importance  FlexFloat `json:"importance"`
+	Level       FlexFloat `json:"level"`
+}
+
+// AttainmentLevel is the share of workers in an occupation with a given
+// education, labelled as in typicalEdLevel.
+type AttainmentLevel struct {
+	Level   string  `json:"level"`
+	Percent float64 `json:"percent"`
+}
+
+// FlexFloat decodes a JSON number that may also be encoded as a string, which
+// is how the O*NET export ships importance and level scores.
+type FlexFloat float64
+
+func (f *FlexFloat) UnmarshalJSON(b []byte) error {
+	s := strings.Trim(string(b), `"`)
+	if s == "" || s == "null" {
+		*f = 0
+		return nil
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return fmt.Errorf("invalid number %s: %w", b, err)
+	}
+	*f = FlexFloat(v)
+	return nil
+}
+
+// UnmarshalJSON also accepts the snake_case names Occupation uses for the core
+// fields, so bodies written for the original write API keep working.
+func (r *OccupationRecord) UnmarshalJSON(b []byte) error {
+	type record OccupationRecord
+	if err := json.Unmarshal(b, (*record)(r)); err != nil {
+		return err
+	}
+	if r.SocID != "" && r.SocTitle != "" && r.SingularTitle != "" && r.TypicalEdLevel != "" {
+		return nil
+	}
+
+	var legacy struct {
+		SocID          string `json:"soc_id"`
+		SocTitle       string `json:"soc_title"`
+		SingularTitle  string `json:"singular_title"`
+		TypicalEdLevel string `json:"typical_ed_level"`
+	}
+	if err := json.Unmarshal(b, &legacy); err != nil {
+		return err
+	}
+	r.SocID = firstNonEmpty(r.SocID, legacy.SocID)
+	r.SocTitle = firstNonEmpty(r.SocTitle, legacy.SocTitle)
+	r.SingularTitle = firstNonEmpty(r.SingularTitle, legacy.SingularTitle)
+	r.TypicalEdLevel = firstNonEmpty(r.TypicalEdLevel, legacy.TypicalEdLevel)
+	return nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// Occupation returns the record's core occupation fields.
+func (r *OccupationRecord) Occupation() Occupation {
+	return Occupation{
+		ID:             r.ID,
+		SocID:          r.SocID,
+		SocTitle:       r.SocTitle,
+		Title:          r.Title,
+		SingularTitle:  r.SingularTitle,
+		Description:    r.Description,
+		TypicalEdLevel: r.TypicalEdLevel,
+	}
+}
+
+// SetOccupation replaces the record's core fields with those of occ.
+func (r *OccupationRecord) SetOccupation(occ Occupation) {
+	r.ID = occ.ID
+	r.SocID = occ.SocID
+	r.SocTitle = occ.SocTitle
+	r.Title = occ.Title
+	r.SingularTitle = occ.SingularTitle
+	r.Description = occ.Description
+	r.TypicalEdLevel = occ.TypicalEdLevel
+}
+
+// Slug returns the record's URL slug, deriving one from the short or
+// singular title when the record has none.
+func (r *OccupationRecord) Slug() string {
+	if r.TitleSlug != "" {
+		return r.TitleSlug
+	}
+	return Slugify(firstNonEmpty(r.ShortTitle, r.SingularTitle, r.Title))
+}
+
+// Slugify turns a title into a URL slug, e.g. "Chief Executive" into
+// "chief-executive".
+func Slugify(title string) string {
+	return strings.Join(strings.FieldsFunc(strings.ToLower(title), func(c rune) bool {
+		return (c < 'a' || c > 'z') && (c < '0' || c > '9')
+	}), "-")
+}
+
+var pathwayPattern = regexp.MustCompile(`^\d{1,4}\.\d{1,4}$`)
+
+func (r *OccupationRecord) Validate() error {
+	occ := r.Occupation()
+	if err := occ.Validate(); err != nil {
+		return err
+	}
+
+	if len(r.TitleSlug) > 255 {
+		return fmt.Errorf("titleSlug exceeds maximum length of 255 characters")
+	}
+	for _, code := range r.Mocs {
+		if _, err := ParseMOC(code); err != nil {
+			return err
+		}
+		if len(code) > 20 {
+			return fmt.Errorf("military occupation code exceeds maximum length of 20 characters: %s", code)
+		}
+	}
+	for _, pathway := range r.Pathways {
+		if !pathwayPattern.MatchString(pathway) {
+			return fmt.Errorf("invalid pathway: %s", pathway)
+		}
+	}
+	for _, titles := range [][]string{r.LayTitles, r.EmsiTitles} {
+		for _, title := range titles {
+			if title == "" || len(title) > 255 {
+				return fmt.Errorf("alternate titles must be between 1 and 255 characters")
+			}
+		}
+	}
+	for _, task := range r.CoreTasks {
+		if task == "" {
+			return fmt.Errorf("coreTasks must not contain empty tasks")
+		}
+	}
+
+	for _, group := range []struct {
+		kind         string
+		competencies []CompetencyRecord
+	}{{"skills", r.Skills}, {"knowledge", r.Knowledge}, {"abilities", r.Abilities}} {
+		kind := group.kind
+		for _, c := range group.competencies {
+			if c.Name == "" || len(c.Name) > 255 {
+				return fmt.Errorf("%s names must be between 1 and 255 characters", kind)
+			}
+			if c.Importance < 0 || c.Importance > 5 {
+				return fmt.Errorf("%s importance must be between 0 and 5: %s", kind, c.Name)
+			}
+			if c.Level < 0 || c.Level >= 10000 {
+				return fmt.Errorf("%s level must be between 0 and 10000: %s", kind, c.Name)
+			}
+		}
+	}
+
+	if r.RiasecTraits != nil {
+		if err := r.RiasecTraits.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
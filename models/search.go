@@ -0,0 +1,26 @@
+package models
+
+// Sources of alternate titles in the occupation_alt_titles table.
+const (
+	AltTitleLay  = "lay"
+	AltTitleEmsi = "emsi"
+)
+
+// SearchResult is an occupation matched by a search, with its relevance
+// score (higher is better). When the query matched one of the occupation's
+// alternate titles, MatchedTitle reports the best one and its source.
+type SearchResult struct {
+	Occupation
+	Score              float64 `json:"score"`
+	MatchedTitle       string  `json:"matched_title,omitempty"`
+	MatchedTitleSource string  `json:"matched_title_source,omitempty"`
+}
+
+// Suggestion is a typeahead match: the matched text, which field it came
+// from, and the occupation it belongs to.
+type Suggestion struct {
+	Text         string `json:"text"`
+	Field        string `json:"field"`
+	OccupationID string `json:"occupation_id"`
+	Title        string `json:"title"`
+}
@@ -0,0 +1,28 @@
+package models
+
+// Similarity sources accepted by GET /occupations/{id}/similar?by=.
+const (
+	SimilarOccs      = "occs"
+	SimilarInterests = "interests"
+	SimilarSkills    = "skills"
+	SimilarAll       = "all"
+)
+
+// SimilaritySources lists the individual similarity sources in the order
+// they are consulted when combined.
+var SimilaritySources = []string{SimilarOccs, SimilarInterests, SimilarSkills}
+
+// SimilarityLists maps each similarity source to the data JSON key holding
+// its ranked list of occupation ids.
+var SimilarityLists = map[string]string{
+	SimilarOccs:      "similarOccs",
+	SimilarInterests: "similarByCapabilitiesInterests",
+	SimilarSkills:    "similarBySkillsExperience",
+}
+
+// SimilarOccupation is an occupation listed as similar to another, tagged
+// with the similarity sources that listed it.
+type SimilarOccupation struct {
+	Occupation
+	Sources []string `json:"sources"`
+}
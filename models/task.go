@@ -0,0 +1,14 @@
+package models
+
+type Task struct {
+	ID   int    `json:"id"`
+	Task string `json:"task"`
+}
+
+// TaskMatch is an occupation returned by a task search together with the
+// tasks that matched the query, best match first.
+type TaskMatch struct {
+	Occupation
+	Score        float64  `json:"score"`
+	MatchedTasks []string `json:"matched_tasks"`
+}
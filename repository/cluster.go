@@ -0,0 +1,175 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-careers/models"
+)
+
+func (r *OccupationRepository) GetClusters(ctx context.Context) ([]models.CareerCluster, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first
+	cacheKey := "clusters:all"
+	var clusters []models.CareerCluster
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &clusters); err == nil {
+			return clusters, nil
+		}
+	}
+
+	// Cache miss - query database
+	query := `
+		SELECT c.id, COALESCE(c.name, ''), COUNT(DISTINCT p.id), COUNT(DISTINCT op.occupation_id)
+		FROM career_clusters c
+		LEFT JOIN career_pathways p ON p.cluster_id = c.id
+		LEFT JOIN occupation_pathways op ON op.pathway_id = p.id
+		GROUP BY c.id, c.name
+		ORDER BY c.id
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	clusters = []models.CareerCluster{}
+	for rows.Next() {
+		var c models.CareerCluster
+		if err := rows.Scan(&c.ID, &c.Name, &c.PathwayCount, &c.OccupationCount); err != nil {
+			return nil, err
+		}
+		clusters = append(clusters, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, clusters, time.Hour)
+	}
+
+	return clusters, nil
+}
+
+func (r *OccupationRepository) GetPathways(ctx context.Context, clusterID int) ([]models.CareerPathway, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first
+	cacheKey := fmt.Sprintf("clusters:%d:pathways", clusterID)
+	var pathways []models.CareerPathway
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &pathways); err == nil {
+			return pathways, nil
+		}
+	}
+
+	// Cache miss - query database
+	query := `
+		SELECT p.id, p.cluster_id, COALESCE(p.name, ''), COUNT(DISTINCT op.occupation_id)
+		FROM career_pathways p
+		LEFT JOIN occupation_pathways op ON op.pathway_id = p.id
+		WHERE p.cluster_id = ?
+		GROUP BY p.id, p.cluster_id, p.name
+		ORDER BY CAST(SUBSTRING_INDEX(p.id, '.', -1) AS UNSIGNED)
+	`
+	rows, err := r.db.QueryContext(ctx, query, clusterID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pathways = []models.CareerPathway{}
+	for rows.Next() {
+		var p models.CareerPathway
+		if err := rows.Scan(&p.ID, &p.ClusterID, &p.Name, &p.OccupationCount); err != nil {
+			return nil, err
+		}
+		pathways = append(pathways, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, pathways, time.Hour)
+	}
+
+	return pathways, nil
+}
+
+func (r *OccupationRepository) GetPathway(ctx context.Context, id string) (*models.CareerPathway, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	query := `
+		SELECT p.id, p.cluster_id, COALESCE(p.name, ''), COUNT(DISTINCT op.occupation_id)
+		FROM career_pathways p
+		LEFT JOIN occupation_pathways op ON op.pathway_id = p.id
+		WHERE p.id = ?
+		GROUP BY p.id, p.cluster_id, p.name
+	`
+	var p models.CareerPathway
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&p.ID, &p.ClusterID, &p.Name, &p.OccupationCount)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &p, nil
+}
+
+func (r *OccupationRepository) GetPathwayOccupations(ctx context.Context, id string) ([]models.Occupation, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first
+	cacheKey := fmt.Sprintf("clusters:pathway:%s:occupations", id)
+	var occupations []models.Occupation
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &occupations); err == nil {
+			return occupations, nil
+		}
+	}
+
+	// Cache miss - query database
+	query := `
+		SELECT o.id, o.soc_id, o.soc_title, o.title, o.singular_title, o.description, o.typical_ed_level
+		FROM occupation_pathways op
+		JOIN occupations o ON o.id = op.occupation_id
+		WHERE op.pathway_id = ?
+		ORDER BY o.title
+	`
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	occupations = []models.Occupation{}
+	for rows.Next() {
+		var occ models.Occupation
+		if err := rows.Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel); err != nil {
+			return nil, err
+		}
+		occupations = append(occupations, occ)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, occupations, time.Hour)
+	}
+
+	return occupations, nil
+}
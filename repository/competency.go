@@ -0,0 +1,69 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-careers/models"
+)
+
+// competencyColumns maps each competency type to the table the seed converter
+// loads it into and the prefix used by that table's name/description columns.
+var competencyColumns = map[models.CompetencyType]struct {
+	table  string
+	prefix string
+}{
+	models.CompetencySkills:    {"occupation_skills", "skill"},
+	models.CompetencyKnowledge: {"occupation_knowledge", "knowledge"},
+	models.CompetencyAbilities: {"occupation_abilities", "ability"},
+}
+
+func (r *OccupationRepository) GetCompetencies(ctx context.Context, id string, kind models.CompetencyType) ([]models.Competency, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	cols, ok := competencyColumns[kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown competency type: %s", kind)
+	}
+
+	// Try cache first
+	cacheKey := fmt.Sprintf("%s:%s", kind, id)
+	var competencies []models.Competency
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &competencies); err == nil {
+			return competencies, nil
+		}
+	}
+
+	// Cache miss - query database
+	query := fmt.Sprintf(
+		"SELECT %[2]s_name, %[2]s_description, importance, level FROM %[1]s WHERE occupation_id = ? ORDER BY importance DESC, level DESC",
+		cols.table, cols.prefix,
+	)
+	rows, err := r.db.QueryContext(ctx, query, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	competencies = []models.Competency{}
+	for rows.Next() {
+		var c models.Competency
+		if err := rows.Scan(&c.Name, &c.Description, &c.Importance, &c.Level); err != nil {
+			return nil, err
+		}
+		competencies = append(competencies, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, competencies, time.Hour)
+	}
+
+	return competencies, nil
+}
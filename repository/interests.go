@@ -0,0 +1,108 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"sort"
+	"time"
+
+	"go-careers/models"
+)
+
+// occupationInterests pairs an occupation with its RIASEC profile.
+type occupationInterests struct {
+	Occupation models.Occupation
+	Profile    models.InterestProfile
+}
+
+// getInterestProfiles loads the RIASEC profile of every occupation that has
+// one. Profiles only live in the data JSON.
+func (r *OccupationRepository) getInterestProfiles(ctx context.Context) ([]occupationInterests, error) {
+	// Try cache first
+	cacheKey := "interests:all"
+	var profiles []occupationInterests
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &profiles); err == nil {
+			return profiles, nil
+		}
+	}
+
+	// Cache miss - query database
+	query := `
+		SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level,
+			JSON_EXTRACT(data, '$.riasecTraits')
+		FROM occupations
+		WHERE JSON_EXTRACT(data, '$.riasecTraits') IS NOT NULL
+		ORDER BY id
+	`
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	profiles = []occupationInterests{}
+	for rows.Next() {
+		var p occupationInterests
+		var traits sql.NullString
+		if err := rows.Scan(&p.Occupation.ID, &p.Occupation.SocID, &p.Occupation.SocTitle, &p.Occupation.Title, &p.Occupation.SingularTitle, &p.Occupation.Description, &p.Occupation.TypicalEdLevel, &traits); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(traits.String), &p.Profile); err != nil {
+			return nil, err
+		}
+		profiles = append(profiles, p)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, profiles, time.Hour)
+	}
+
+	return profiles, nil
+}
+
+// MatchInterests ranks occupations by how closely their RIASEC profile
+// matches the given one, using the similarity method named by method.
+func (r *OccupationRepository) MatchInterests(ctx context.Context, profile models.InterestProfile, method string, limit int) ([]models.InterestMatch, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	profiles, err := r.getInterestProfiles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return rankInterests(profiles, profile, method, limit), nil
+}
+
+// rankInterests scores every profile against profile and returns the best
+// limit matches.
+func rankInterests(profiles []occupationInterests, profile models.InterestProfile, method string, limit int) []models.InterestMatch {
+	matches := []models.InterestMatch{}
+	for _, p := range profiles {
+		score, contributions, ok := profile.Similarity(p.Profile, method)
+		if !ok {
+			continue
+		}
+		matches = append(matches, models.InterestMatch{
+			Occupation:    p.Occupation,
+			Score:         score,
+			Profile:       p.Profile,
+			Contributions: contributions,
+		})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches
+}
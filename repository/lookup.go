@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-careers/models"
+)
+
+// GetBySlug resolves an occupation from its URL slug, e.g. "chief-executive".
+func (r *OccupationRepository) GetBySlug(ctx context.Context, slug string) (*models.Occupation, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	var id string
+	err := r.db.QueryRowContext(ctx, "SELECT id FROM occupations WHERE title_slug = ? ORDER BY id LIMIT 1", slug).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+
+	return r.GetByID(ctx, id)
+}
+
+// GetBySocID returns every detailed O*NET occupation under a SOC code.
+func (r *OccupationRepository) GetBySocID(ctx context.Context, socID string) ([]models.Occupation, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first
+	cacheKey := fmt.Sprintf("soc:%s", socID)
+	var occupations []models.Occupation
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &occupations); err == nil {
+			return occupations, nil
+		}
+	}
+
+	// Cache miss - query database
+	query := "SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level FROM occupations WHERE soc_id = ? ORDER BY id"
+	rows, err := r.db.QueryContext(ctx, query, socID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	occupations = []models.Occupation{}
+	for rows.Next() {
+		var occ models.Occupation
+		if err := rows.Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel); err != nil {
+			return nil, err
+		}
+		occupations = append(occupations, occ)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, occupations, time.Hour)
+	}
+
+	return occupations, nil
+}
+
+// GetSocCodes returns every detailed SOC code in use with its title and the
+// number of O*NET occupations filed under it.
+func (r *OccupationRepository) GetSocCodes(ctx context.Context) ([]models.SocCode, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first
+	cacheKey := "socgroups:codes"
+	var codes []models.SocCode
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &codes); err == nil {
+			return codes, nil
+		}
+	}
+
+	// Cache miss - query database
+	rows, err := r.db.QueryContext(ctx, "SELECT soc_id, MIN(soc_title), COUNT(*) FROM occupations GROUP BY soc_id ORDER BY soc_id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes = []models.SocCode{}
+	for rows.Next() {
+		var c models.SocCode
+		if err := rows.Scan(&c.Code, &c.Title, &c.OccupationCount); err != nil {
+			return nil, err
+		}
+		codes = append(codes, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, codes, time.Hour)
+	}
+
+	return codes, nil
+}
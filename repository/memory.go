@@ -0,0 +1,824 @@
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"go-careers/models"
+	"go-careers/search"
+)
+
+// MemoryStore is an OccupationStore held entirely in process, loaded from a
+// JSONL export such as seed_data/occupations.jsonl. Writes only last as long
+// as the process. It needs no database, so the API can run in demos and
+// tests without MySQL.
+type MemoryStore struct {
+	mu         sync.RWMutex
+	records    map[string]*memoryRecord
+	clusters   map[int]string
+	pathways   map[string]models.CareerPathway
+	nextTaskID int
+
+	searchMu  sync.Mutex
+	snapshot  *searchSnapshot
+	taskIndex *taskIndex
+}
+
+// memoryRecord is a stored record with its tasks numbered as MySQL would.
+type memoryRecord struct {
+	models.OccupationRecord
+	tasks []models.Task
+}
+
+// taskIndex is a search index over every core task. Document ids are
+// positions in tasks.
+type taskIndex struct {
+	index *search.Index
+	tasks []taskEntry
+}
+
+type taskEntry struct {
+	occupationID string
+	task         string
+}
+
+// NewMemoryStore loads every record of occupationsFile, one JSON object per
+// line, and the cluster and pathway names of clustersFile if it is not
+// empty. Any invalid or duplicate record is an error.
+func NewMemoryStore(occupationsFile, clustersFile string) (*MemoryStore, error) {
+	s := &MemoryStore{
+		records:  map[string]*memoryRecord{},
+		clusters: map[int]string{},
+		pathways: map[string]models.CareerPathway{},
+	}
+
+	if clustersFile != "" {
+		data, err := os.ReadFile(clustersFile)
+		if err != nil {
+			return nil, fmt.Errorf("error reading clusters file: %w", err)
+		}
+		var clusters []models.ClusterNames
+		if err := json.Unmarshal(data, &clusters); err != nil {
+			return nil, fmt.Errorf("error parsing clusters file: %w", err)
+		}
+		for _, c := range clusters {
+			s.clusters[c.ID] = c.Name
+			for _, p := range c.Pathways {
+				s.pathways[p.ID] = models.CareerPathway{ID: p.ID, ClusterID: c.ID, Name: p.Name}
+			}
+		}
+	}
+
+	f, err := os.Open(occupationsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error opening occupations file: %w", err)
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(f)
+	for line := 1; ; line++ {
+		b, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, fmt.Errorf("error reading occupations file: %w", readErr)
+		}
+
+		if trimmed := bytes.TrimSpace(b); len(trimmed) > 0 {
+			var rec models.OccupationRecord
+			if err := json.Unmarshal(trimmed, &rec); err != nil {
+				return nil, fmt.Errorf("%s line %d: %w", occupationsFile, line, err)
+			}
+			if err := rec.Validate(); err != nil {
+				return nil, fmt.Errorf("%s line %d: %w", occupationsFile, line, err)
+			}
+			if _, ok := s.records[rec.ID]; ok {
+				return nil, fmt.Errorf("%s line %d: duplicate occupation %s", occupationsFile, line, rec.ID)
+			}
+			s.put(rec)
+		}
+
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	return s, nil
+}
+
+// put stores rec, replacing any record with the same id, and registers its
+// pathways. s.mu must be held for writing.
+func (s *MemoryStore) put(rec models.OccupationRecord) {
+	stored := &memoryRecord{OccupationRecord: rec, tasks: []models.Task{}}
+	for _, task := range rec.CoreTasks {
+		s.nextTaskID++
+		stored.tasks = append(stored.tasks, models.Task{ID: s.nextTaskID, Task: task})
+	}
+	s.records[rec.ID] = stored
+
+	// Pathways missing from the names file are added without a name, as the
+	// seed converter does
+	for _, id := range rec.Pathways {
+		if _, ok := s.pathways[id]; !ok {
+			prefix, _, _ := strings.Cut(id, ".")
+			clusterID, _ := strconv.Atoi(prefix)
+			s.pathways[id] = models.CareerPathway{ID: id, ClusterID: clusterID}
+		}
+	}
+}
+
+// occupations returns the occupations whose record satisfies keep, ordered
+// by id. s.mu must be held.
+func (s *MemoryStore) occupations(keep func(*memoryRecord) bool) []models.Occupation {
+	occupations := []models.Occupation{}
+	for _, rec := range s.records {
+		if keep(rec) {
+			occupations = append(occupations, rec.Occupation())
+		}
+	}
+	sort.Slice(occupations, func(i, j int) bool {
+		return occupations[i].ID < occupations[j].ID
+	})
+	return occupations
+}
+
+// sortByTitle orders occupations by title ignoring case, as MySQL's
+// collation does, keeping their id order among equal titles.
+func sortByTitle(occupations []models.Occupation) []models.Occupation {
+	sort.SliceStable(occupations, func(i, j int) bool {
+		return strings.ToLower(occupations[i].Title) < strings.ToLower(occupations[j].Title)
+	})
+	return occupations
+}
+
+func (s *MemoryStore) GetAll(ctx context.Context, opts ListOptions) (*OccupationPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if opts.Sort == "" {
+		opts.Sort = "id"
+	}
+	if _, ok := sortColumns[opts.Sort]; !ok {
+		return nil, fmt.Errorf("unknown sort: %s", opts.Sort)
+	}
+	var cursor *pageCursor
+	if opts.Cursor != "" {
+		c, err := decodeCursor(opts.Cursor, opts.Sort)
+		if err != nil {
+			return nil, err
+		}
+		cursor = c
+	}
+
+	s.mu.RLock()
+	all := s.occupations(func(rec *memoryRecord) bool {
+		return opts.Education.IsZero() || opts.Education.Matches(rec.TypicalEdLevel)
+	})
+	s.mu.RUnlock()
+
+	// Compare values ignoring case, as MySQL's collation does
+	sort.SliceStable(all, func(i, j int) bool {
+		return strings.ToLower(sortValue(opts.Sort, all[i])) < strings.ToLower(sortValue(opts.Sort, all[j]))
+	})
+
+	page := &OccupationPage{Occupations: []models.Occupation{}, Total: len(all)}
+	start := 0
+	if cursor != nil {
+		after := strings.ToLower(cursor.Value)
+		start = sort.Search(len(all), func(i int) bool {
+			v := strings.ToLower(sortValue(opts.Sort, all[i]))
+			return v > after || (v == after && all[i].ID > cursor.ID)
+		})
+	}
+	end := min(start+opts.Limit, len(all))
+	page.Occupations = append(page.Occupations, all[start:end]...)
+
+	if end < len(all) {
+		last := all[end-1]
+		page.NextCursor = encodeCursor(pageCursor{Sort: opts.Sort, Value: sortValue(opts.Sort, last), ID: last.ID})
+	}
+
+	return page, nil
+}
+
+func (s *MemoryStore) GetByID(ctx context.Context, id string) (*models.Occupation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.records[id]
+	if !ok {
+		return nil, nil
+	}
+
+	occ := rec.Occupation()
+	occ.EducationLevel = models.EducationLevelFromLabel(occ.TypicalEdLevel)
+	occ.EducationDistribution = models.NewEducationDistribution(rec.EducationAttainmentLevels)
+	return &occ, nil
+}
+
+// GetBySlug resolves an occupation from its URL slug, e.g. "chief-executive".
+func (s *MemoryStore) GetBySlug(ctx context.Context, slug string) (*models.Occupation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	matches := s.occupations(func(rec *memoryRecord) bool {
+		return rec.Slug() == slug
+	})
+	s.mu.RUnlock()
+
+	if len(matches) == 0 {
+		return nil, nil
+	}
+	return s.GetByID(ctx, matches[0].ID)
+}
+
+// GetBySocID returns every detailed O*NET occupation under a SOC code.
+func (s *MemoryStore) GetBySocID(ctx context.Context, socID string) ([]models.Occupation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.occupations(func(rec *memoryRecord) bool {
+		return rec.SocID == socID
+	}), nil
+}
+
+// GetSocCodes returns every detailed SOC code in use with its title and the
+// number of O*NET occupations filed under it.
+func (s *MemoryStore) GetSocCodes(ctx context.Context) ([]models.SocCode, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byCode := map[string]*models.SocCode{}
+	for _, rec := range s.records {
+		c, ok := byCode[rec.SocID]
+		if !ok {
+			c = &models.SocCode{Code: rec.SocID, Title: rec.SocTitle}
+			byCode[rec.SocID] = c
+		}
+		c.Title = min(c.Title, rec.SocTitle)
+		c.OccupationCount++
+	}
+
+	codes := make([]models.SocCode, 0, len(byCode))
+	for _, c := range byCode {
+		codes = append(codes, *c)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return codes[i].Code < codes[j].Code
+	})
+	return codes, nil
+}
+
+// GetSimilar returns the occupations listed as similar to id; see
+// OccupationRepository.GetSimilar.
+func (s *MemoryStore) GetSimilar(ctx context.Context, id, by string) ([]models.SimilarOccupation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	similar := []models.SimilarOccupation{}
+	rec, ok := s.records[id]
+	if !ok {
+		return similar, nil
+	}
+
+	ids, sources := rankSimilar(map[string][]string{
+		models.SimilarOccs:      rec.SimilarOccs,
+		models.SimilarInterests: rec.SimilarByCapabilitiesInterests,
+		models.SimilarSkills:    rec.SimilarBySkillsExperience,
+	}, by)
+
+	// Listed occupations missing from the store are skipped
+	for _, similarID := range ids {
+		if other, ok := s.records[similarID]; ok {
+			similar = append(similar, models.SimilarOccupation{
+				Occupation: other.Occupation(),
+				Sources:    sources[similarID],
+			})
+		}
+	}
+	return similar, nil
+}
+
+func (s *MemoryStore) GetCompetencies(ctx context.Context, id string, kind models.CompetencyType) ([]models.Competency, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	if _, ok := competencyColumns[kind]; !ok {
+		return nil, fmt.Errorf("unknown competency type: %s", kind)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	competencies := []models.Competency{}
+	rec, ok := s.records[id]
+	if !ok {
+		return competencies, nil
+	}
+
+	records := map[models.CompetencyType][]models.CompetencyRecord{
+		models.CompetencySkills:    rec.Skills,
+		models.CompetencyKnowledge: rec.Knowledge,
+		models.CompetencyAbilities: rec.Abilities,
+	}[kind]
+	for _, c := range records {
+		competencies = append(competencies, models.Competency{
+			Name:        c.Name,
+			Description: c.Description,
+			Importance:  float64(c.Importance),
+			Level:       float64(c.Level),
+		})
+	}
+	sort.SliceStable(competencies, func(i, j int) bool {
+		if competencies[i].Importance != competencies[j].Importance {
+			return competencies[i].Importance > competencies[j].Importance
+		}
+		return competencies[i].Level > competencies[j].Level
+	})
+	return competencies, nil
+}
+
+func (s *MemoryStore) GetTasks(ctx context.Context, id string) ([]models.Task, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tasks := []models.Task{}
+	if rec, ok := s.records[id]; ok {
+		tasks = append(tasks, rec.tasks...)
+	}
+	return tasks, nil
+}
+
+// GetMilitaryCodes returns the military occupation codes that crosswalk to an
+// occupation.
+func (s *MemoryStore) GetMilitaryCodes(ctx context.Context, id string) ([]models.MilitaryCode, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	codes := []models.MilitaryCode{}
+	rec, ok := s.records[id]
+	if !ok {
+		return codes, nil
+	}
+
+	mocs := append([]string{}, rec.Mocs...)
+	sort.Strings(mocs)
+	for _, code := range mocs {
+		moc, err := models.ParseMOC(code)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, moc)
+	}
+	return codes, nil
+}
+
+// GetByMilitaryCode returns the civilian occupations a military occupation
+// code crosswalks to, matching the code ignoring case.
+func (s *MemoryStore) GetByMilitaryCode(ctx context.Context, code string) ([]models.Occupation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortByTitle(s.occupations(func(rec *memoryRecord) bool {
+		return slices.ContainsFunc(rec.Mocs, func(moc string) bool {
+			return strings.EqualFold(moc, code)
+		})
+	})), nil
+}
+
+// pathwayOccupations counts the occupations in each pathway and in each
+// cluster. s.mu must be held.
+func (s *MemoryStore) pathwayOccupations() (map[string]int, map[int]int) {
+	byPathway, byCluster := map[string]int{}, map[int]int{}
+	for _, rec := range s.records {
+		clusters := map[int]bool{}
+		for _, id := range rec.Pathways {
+			byPathway[id]++
+			clusters[s.pathways[id].ClusterID] = true
+		}
+		for clusterID := range clusters {
+			byCluster[clusterID]++
+		}
+	}
+	return byPathway, byCluster
+}
+
+func (s *MemoryStore) GetClusters(ctx context.Context) ([]models.CareerCluster, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, byCluster := s.pathwayOccupations()
+	pathwayCounts := map[int]int{}
+	for _, p := range s.pathways {
+		pathwayCounts[p.ClusterID]++
+	}
+
+	clusters := []models.CareerCluster{}
+	for id, name := range s.clusters {
+		clusters = append(clusters, models.CareerCluster{
+			ID:              id,
+			Name:            name,
+			PathwayCount:    pathwayCounts[id],
+			OccupationCount: byCluster[id],
+		})
+	}
+	sort.Slice(clusters, func(i, j int) bool {
+		return clusters[i].ID < clusters[j].ID
+	})
+	return clusters, nil
+}
+
+func (s *MemoryStore) GetPathways(ctx context.Context, clusterID int) ([]models.CareerPathway, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	byPathway, _ := s.pathwayOccupations()
+	pathways := []models.CareerPathway{}
+	for _, p := range s.pathways {
+		if p.ClusterID == clusterID {
+			p.OccupationCount = byPathway[p.ID]
+			pathways = append(pathways, p)
+		}
+	}
+
+	// Order by the number after the dot, so 14.10 follows 14.9
+	number := func(id string) int {
+		_, n, _ := strings.Cut(id, ".")
+		v, _ := strconv.Atoi(n)
+		return v
+	}
+	sort.Slice(pathways, func(i, j int) bool {
+		return number(pathways[i].ID) < number(pathways[j].ID)
+	})
+	return pathways, nil
+}
+
+func (s *MemoryStore) GetPathway(ctx context.Context, id string) (*models.CareerPathway, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	p, ok := s.pathways[id]
+	if !ok {
+		return nil, nil
+	}
+	byPathway, _ := s.pathwayOccupations()
+	p.OccupationCount = byPathway[id]
+	return &p, nil
+}
+
+func (s *MemoryStore) GetPathwayOccupations(ctx context.Context, id string) ([]models.Occupation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortByTitle(s.occupations(func(rec *memoryRecord) bool {
+		return containsString(rec.Pathways, id)
+	})), nil
+}
+
+// loadSearchSnapshot returns the current search index, building it on first
+// use and after a write.
+func (s *MemoryStore) loadSearchSnapshot(ctx context.Context) *searchSnapshot {
+	s.searchMu.Lock()
+	defer s.searchMu.Unlock()
+
+	if s.snapshot != nil {
+		return s.snapshot
+	}
+
+	s.mu.RLock()
+	entries := make([]searchEntry, 0, len(s.records))
+	for _, rec := range s.records {
+		entries = append(entries, searchEntry{
+			occupation: rec.Occupation(),
+			shortTitle: rec.ShortTitle,
+			layTitles:  rec.LayTitles,
+			emsiTitles: rec.EmsiTitles,
+		})
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].occupation.ID < entries[j].occupation.ID
+	})
+	s.snapshot = newSearchSnapshot(entries)
+	return s.snapshot
+}
+
+// loadTaskIndex returns the current task index, building it on first use
+// and after a write.
+func (s *MemoryStore) loadTaskIndex(ctx context.Context) *taskIndex {
+	s.searchMu.Lock()
+	defer s.searchMu.Unlock()
+
+	if s.taskIndex != nil {
+		return s.taskIndex
+	}
+
+	ix := &taskIndex{}
+	s.mu.RLock()
+	for _, occ := range s.occupations(func(*memoryRecord) bool { return true }) {
+		for _, t := range s.records[occ.ID].tasks {
+			ix.tasks = append(ix.tasks, taskEntry{occupationID: occ.ID, task: t.Task})
+		}
+	}
+	s.mu.RUnlock()
+
+	docs := make([]search.Document, len(ix.tasks))
+	for i, t := range ix.tasks {
+		docs[i] = search.Document{ID: strconv.Itoa(i), Fields: map[string][]string{"task": {t.task}}}
+	}
+	ix.index = search.NewIndex(docs, map[string]float64{"task": 1})
+	s.taskIndex = ix
+	return ix
+}
+
+// invalidateSearch drops the search and task indexes so the next search sees
+// the latest occupations. s.mu must not be held.
+func (s *MemoryStore) invalidateSearch(ctx context.Context) {
+	s.searchMu.Lock()
+	s.snapshot = nil
+	s.taskIndex = nil
+	s.searchMu.Unlock()
+}
+
+func (s *MemoryStore) Search(ctx context.Context, opts SearchOptions) (*SearchPage, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	page := s.loadSearchSnapshot(ctx).search(opts)
+	return &page, nil
+}
+
+// Autocomplete returns up to limit typeahead suggestions for a title prefix
+// across titles, singular and short titles, and lay titles.
+func (s *MemoryStore) Autocomplete(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return s.loadSearchSnapshot(ctx).autocomplete(prefix, limit), nil
+}
+
+// SearchTasks finds occupations whose core tasks match the search term.
+// Tasks are ranked by the in-process search index rather than MySQL's
+// FULLTEXT relevance, so scores differ between the two stores; occupations
+// are still ranked by the summed score of their matching tasks.
+func (s *MemoryStore) SearchTasks(ctx context.Context, searchTerm string, limit int) ([]models.TaskMatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	ix := s.loadTaskIndex(ctx)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Group matching tasks by occupation, keeping the best tasks first
+	byID := map[string]*models.TaskMatch{}
+	var order []string
+	for _, hit := range ix.index.Search(searchTerm) {
+		i, _ := strconv.Atoi(hit.ID)
+		t := ix.tasks[i]
+		rec, ok := s.records[t.occupationID]
+		if !ok {
+			continue
+		}
+
+		m, ok := byID[t.occupationID]
+		if !ok {
+			m = &models.TaskMatch{Occupation: rec.Occupation(), MatchedTasks: []string{}}
+			byID[t.occupationID] = m
+			order = append(order, t.occupationID)
+		}
+		m.Score += hit.Score
+		if len(m.MatchedTasks) < maxMatchedTasks {
+			m.MatchedTasks = append(m.MatchedTasks, t.task)
+		}
+	}
+
+	matches := make([]models.TaskMatch, 0, len(order))
+	for _, id := range order {
+		matches = append(matches, *byID[id])
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	return matches, nil
+}
+
+// MatchInterests ranks occupations by how closely their RIASEC profile
+// matches the given one, using the similarity method named by method.
+func (s *MemoryStore) MatchInterests(ctx context.Context, profile models.InterestProfile, method string, limit int) ([]models.InterestMatch, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	profiles := []occupationInterests{}
+	for _, occ := range s.occupations(func(rec *memoryRecord) bool { return rec.RiasecTraits != nil }) {
+		profiles = append(profiles, occupationInterests{Occupation: occ, Profile: *s.records[occ.ID].RiasecTraits})
+	}
+	s.mu.RUnlock()
+
+	return rankInterests(profiles, profile, method, limit), nil
+}
+
+// CreateBatch writes occupation records according to opts and reports the
+// outcome of each one, with the same results as
+// OccupationRepository.CreateBatch.
+func (s *MemoryStore) CreateBatch(ctx context.Context, records []models.OccupationRecord, opts BatchOptions) ([]models.BatchResult, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	results, failed := validateBatch(records)
+	if failed && opts.Atomic {
+		return rollBackResults(results), ErrBatchRolledBack
+	}
+
+	s.mu.Lock()
+
+	// Decide every outcome before writing anything, so an atomic batch can
+	// still be rejected as a whole
+	created := map[string]bool{}
+	for i, rec := range records {
+		if results[i].Status == models.BatchFailed {
+			continue
+		}
+		_, exists := s.records[rec.ID]
+		switch {
+		case !exists && !created[rec.ID]:
+			results[i].Status = models.BatchCreated
+			created[rec.ID] = true
+		case opts.Mode == models.BatchUpsert:
+			results[i].Status = models.BatchUpdated
+		case opts.Mode == models.BatchSkipExisting:
+			results[i].Status = models.BatchSkipped
+			results[i].Reason = "occupation already exists"
+		default:
+			results[i].Status = models.BatchFailed
+			results[i].Reason = "occupation already exists"
+			failed = true
+		}
+	}
+	if failed && opts.Atomic {
+		s.mu.Unlock()
+		return rollBackResults(results), ErrBatchRolledBack
+	}
+
+	written := false
+	for i, rec := range records {
+		if status := results[i].Status; status == models.BatchCreated || status == models.BatchUpdated {
+			s.put(rec)
+			written = true
+		}
+	}
+	s.mu.Unlock()
+
+	if written {
+		s.invalidateSearch(ctx)
+	}
+	return results, nil
+}
+
+// Update replaces an existing occupation with rec. It returns ErrNotFound
+// when no occupation has the record's id.
+func (s *MemoryStore) Update(ctx context.Context, rec models.OccupationRecord) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	if err := rec.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidOccupation, err)
+	}
+
+	s.mu.Lock()
+	if _, ok := s.records[rec.ID]; !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	s.put(rec)
+	s.mu.Unlock()
+
+	s.invalidateSearch(ctx)
+	return nil
+}
+
+// Patch applies a JSON Merge Patch (RFC 7396) to the core fields of an
+// existing occupation and returns the result. It returns ErrNotFound when no
+// occupation has the given id.
+func (s *MemoryStore) Patch(ctx context.Context, id string, patch []byte) (*models.Occupation, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	rec, ok := s.records[id]
+	if !ok {
+		s.mu.Unlock()
+		return nil, ErrNotFound
+	}
+	current := rec.Occupation()
+	occ, err := applyPatch(current, patch)
+	if err != nil {
+		s.mu.Unlock()
+		return nil, err
+	}
+	rec.SetOccupation(occ)
+	if slug := patchedSlug(current, occ); slug != "" {
+		rec.TitleSlug = slug
+	}
+	s.mu.Unlock()
+
+	s.invalidateSearch(ctx)
+	return &occ, nil
+}
+
+// Delete removes an occupation. It returns ErrNotFound when no occupation
+// has the given id.
+func (s *MemoryStore) Delete(ctx context.Context, id string) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	if _, ok := s.records[id]; !ok {
+		s.mu.Unlock()
+		return ErrNotFound
+	}
+	delete(s.records, id)
+	s.mu.Unlock()
+
+	s.invalidateSearch(ctx)
+	return nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,328 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"go-careers/models"
+)
+
+func newTestStore(t *testing.T) *MemoryStore {
+	t.Helper()
+	s, err := NewMemoryStore("../seed_data/occupations.jsonl", "../seed_data/career_clusters.json")
+	if err != nil {
+		t.Fatalf("NewMemoryStore: %v", err)
+	}
+	return s
+}
+
+func testRecord(id, title string) models.OccupationRecord {
+	return models.OccupationRecord{
+		ID:             id,
+		SocID:          id[:7],
+		SocTitle:       title,
+		Title:          title,
+		SingularTitle:  title,
+		Description:    "Test occupation " + title,
+		TypicalEdLevel: "a Bachelor's degree",
+		CoreTasks:      []string{"Calibrate widget assemblies"},
+	}
+}
+
+func TestNewMemoryStoreRejectsBadFiles(t *testing.T) {
+	tests := []struct {
+		name  string
+		lines string
+	}{
+		{"invalid JSON", "{\n"},
+		{"invalid record", `{"id":"99-0001.00"}` + "\n"},
+		{"duplicate", `{"id":"99-0001.00","socId":"99-0001","socTitle":"A","title":"A","singularTitle":"A","description":"A"}` + "\n" +
+			`{"id":"99-0001.00","socId":"99-0001","socTitle":"A","title":"A","singularTitle":"A","description":"A"}` + "\n"},
+	}
+	for _, tt := range tests {
+		path := filepath.Join(t.TempDir(), "occupations.jsonl")
+		if err := os.WriteFile(path, []byte(tt.lines), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := NewMemoryStore(path, ""); err == nil {
+			t.Errorf("%s: NewMemoryStore succeeded, want an error", tt.name)
+		}
+	}
+}
+
+func TestMemoryStoreLookups(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	occ, err := s.GetByID(ctx, "11-1011.00")
+	if err != nil || occ == nil {
+		t.Fatalf("GetByID = %v, %v", occ, err)
+	}
+	if occ.Title != "Chief Executives" || occ.EducationLevel != models.EducationMaster {
+		t.Errorf("GetByID = %q at %q, want Chief Executives at %q", occ.Title, occ.EducationLevel, models.EducationMaster)
+	}
+
+	if occ, err := s.GetByID(ctx, "99-9999.00"); occ != nil || err != nil {
+		t.Errorf("GetByID(missing) = %v, %v, want nil, nil", occ, err)
+	}
+
+	bySlug, err := s.GetBySlug(ctx, "financial-analyst")
+	if err != nil || bySlug == nil || bySlug.ID != "13-2051.00" {
+		t.Errorf("GetBySlug(financial-analyst) = %v, %v, want 13-2051.00", bySlug, err)
+	}
+
+	tasks, err := s.GetTasks(ctx, "11-1011.00")
+	if err != nil || len(tasks) != 20 {
+		t.Errorf("GetTasks = %d tasks, %v, want 20", len(tasks), err)
+	}
+
+	pathway, err := s.GetPathway(ctx, "14.2")
+	if err != nil || pathway == nil || pathway.ClusterID != 14 || pathway.OccupationCount == 0 {
+		t.Errorf("GetPathway(14.2) = %+v, %v", pathway, err)
+	}
+}
+
+func TestMemoryStoreGetAllPages(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	seen := map[string]bool{}
+	opts := ListOptions{Limit: 30, Sort: "title"}
+	pages := 0
+	for {
+		page, err := s.GetAll(ctx, opts)
+		if err != nil {
+			t.Fatalf("GetAll: %v", err)
+		}
+		pages++
+		for _, occ := range page.Occupations {
+			if seen[occ.ID] {
+				t.Fatalf("%s returned twice", occ.ID)
+			}
+			seen[occ.ID] = true
+		}
+		if page.NextCursor == "" {
+			break
+		}
+		opts.Cursor = page.NextCursor
+	}
+	if len(seen) != 100 || pages != 4 {
+		t.Errorf("paged through %d occupations in %d pages, want 100 in 4", len(seen), pages)
+	}
+
+	if _, err := s.GetAll(ctx, ListOptions{Limit: 10, Sort: "id", Cursor: "garbage"}); !errors.Is(err, ErrInvalidCursor) {
+		t.Errorf("GetAll with a bad cursor = %v, want ErrInvalidCursor", err)
+	}
+}
+
+func TestMemoryStoreIgnoresCase(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	lower := testRecord("99-0001.00", "aaa widget calibrators")
+	if _, err := s.CreateBatch(ctx, []models.OccupationRecord{lower}, BatchOptions{Mode: models.BatchInsert}); err != nil {
+		t.Fatalf("CreateBatch: %v", err)
+	}
+
+	page, err := s.GetAll(ctx, ListOptions{Limit: 2, Sort: "title"})
+	if err != nil {
+		t.Fatalf("GetAll: %v", err)
+	}
+	if len(page.Occupations) == 0 {
+		t.Fatal("GetAll by title returned no occupations")
+	}
+	if first := page.Occupations[0].ID; first != lower.ID {
+		t.Fatalf("GetAll by title starts with %s, want %s", first, lower.ID)
+	}
+	next, err := s.GetAll(ctx, ListOptions{Limit: 200, Sort: "title", Cursor: page.NextCursor})
+	if err != nil {
+		t.Fatalf("GetAll after the cursor: %v", err)
+	}
+	if len(next.Occupations) != 99 {
+		t.Errorf("GetAll after the cursor = %d occupations, want 99", len(next.Occupations))
+	}
+
+	upper, err := s.GetByMilitaryCode(ctx, "A_O_00B")
+	if err != nil || len(upper) == 0 {
+		t.Fatalf("GetByMilitaryCode(A_O_00B) = %d occupations, %v", len(upper), err)
+	}
+	folded, err := s.GetByMilitaryCode(ctx, "a_o_00b")
+	if err != nil || len(folded) != len(upper) {
+		t.Errorf("GetByMilitaryCode(a_o_00b) = %d occupations, %v, want %d", len(folded), err, len(upper))
+	}
+}
+
+func TestMemoryStoreCreateBatch(t *testing.T) {
+	existing := testRecord("11-1011.00", "Chief Executives")
+	fresh := testRecord("99-0001.00", "Widget Calibrators")
+	invalid := models.OccupationRecord{ID: "99-0002.00"}
+
+	tests := []struct {
+		name     string
+		records  []models.OccupationRecord
+		opts     BatchOptions
+		wantErr  error
+		statuses []string
+		written  bool
+	}{
+		{
+			name:     "insert",
+			records:  []models.OccupationRecord{fresh},
+			opts:     BatchOptions{Mode: models.BatchInsert, Atomic: true},
+			statuses: []string{models.BatchCreated},
+			written:  true,
+		},
+		{
+			name:     "atomic rolls back on an existing id",
+			records:  []models.OccupationRecord{fresh, existing},
+			opts:     BatchOptions{Mode: models.BatchInsert, Atomic: true},
+			wantErr:  ErrBatchRolledBack,
+			statuses: []string{models.BatchFailed, models.BatchFailed},
+		},
+		{
+			name:     "atomic rolls back on an invalid item",
+			records:  []models.OccupationRecord{fresh, invalid},
+			opts:     BatchOptions{Mode: models.BatchInsert, Atomic: true},
+			wantErr:  ErrBatchRolledBack,
+			statuses: []string{models.BatchFailed, models.BatchFailed},
+		},
+		{
+			name:     "non-atomic writes the valid items",
+			records:  []models.OccupationRecord{fresh, invalid},
+			opts:     BatchOptions{Mode: models.BatchInsert},
+			statuses: []string{models.BatchCreated, models.BatchFailed},
+			written:  true,
+		},
+		{
+			name:     "skip existing",
+			records:  []models.OccupationRecord{existing, fresh},
+			opts:     BatchOptions{Mode: models.BatchSkipExisting, Atomic: true},
+			statuses: []string{models.BatchSkipped, models.BatchCreated},
+			written:  true,
+		},
+		{
+			name:     "upsert",
+			records:  []models.OccupationRecord{existing},
+			opts:     BatchOptions{Mode: models.BatchUpsert, Atomic: true},
+			statuses: []string{models.BatchUpdated},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestStore(t)
+			ctx := context.Background()
+
+			results, err := s.CreateBatch(ctx, tt.records, tt.opts)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("CreateBatch error = %v, want %v", err, tt.wantErr)
+			}
+			if len(results) != len(tt.statuses) {
+				t.Fatalf("CreateBatch returned %d results, want %d", len(results), len(tt.statuses))
+			}
+			for i, want := range tt.statuses {
+				if results[i].Index != i || results[i].Status != want {
+					t.Errorf("result %d = %d %s, want %s", i, results[i].Index, results[i].Status, want)
+				}
+			}
+
+			occ, _ := s.GetByID(ctx, fresh.ID)
+			if got := occ != nil; got != tt.written {
+				t.Errorf("%s stored = %v, want %v", fresh.ID, got, tt.written)
+			}
+		})
+	}
+}
+
+func TestMemoryStoreWritesReachSearch(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	search := func(q string) int {
+		page, err := s.Search(ctx, SearchOptions{Query: q, Limit: 10})
+		if err != nil {
+			t.Fatalf("Search(%q): %v", q, err)
+		}
+		return page.Total
+	}
+
+	if n := search("calibrator"); n != 0 {
+		t.Fatalf("Search before the write found %d results", n)
+	}
+	if _, err := s.CreateBatch(ctx, []models.OccupationRecord{testRecord("99-0001.00", "Widget Calibrators")}, BatchOptions{Mode: models.BatchInsert, Atomic: true}); err != nil {
+		t.Fatal(err)
+	}
+	if n := search("calibrator"); n != 1 {
+		t.Errorf("Search after create found %d results, want 1", n)
+	}
+	if matches, _ := s.SearchTasks(ctx, "calibrate widget", 10); len(matches) != 1 || matches[0].ID != "99-0001.00" {
+		t.Errorf("SearchTasks after create = %+v, want 99-0001.00", matches)
+	}
+
+	if err := s.Delete(ctx, "99-0001.00"); err != nil {
+		t.Fatal(err)
+	}
+	if n := search("calibrator"); n != 0 {
+		t.Errorf("Search after delete found %d results, want 0", n)
+	}
+}
+
+func TestMemoryStorePatch(t *testing.T) {
+	s := newTestStore(t)
+	ctx := context.Background()
+
+	tests := []struct {
+		name    string
+		patch   string
+		wantErr error
+	}{
+		{"unknown member", `{"foo":1}`, ErrInvalidOccupation},
+		{"read-only member", `{"id":"x"}`, ErrInvalidOccupation},
+		{"clears a required field", `{"title":null}`, ErrInvalidOccupation},
+		{"not an object", `[]`, ErrInvalidOccupation},
+	}
+	for _, tt := range tests {
+		if _, err := s.Patch(ctx, "11-1011.00", []byte(tt.patch)); !errors.Is(err, tt.wantErr) {
+			t.Errorf("%s: Patch error = %v, want %v", tt.name, err, tt.wantErr)
+		}
+	}
+
+	if _, err := s.Patch(ctx, "99-9999.00", []byte(`{"title":"X"}`)); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Patch(missing) error = %v, want ErrNotFound", err)
+	}
+
+	occ, err := s.Patch(ctx, "11-1011.00", []byte(`{"singularTitle":"Top Boss","description":"Runs things."}`))
+	if err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if occ.SingularTitle != "Top Boss" || occ.Description != "Runs things." || occ.Title != "Chief Executives" {
+		t.Errorf("Patch = %+v", occ)
+	}
+	if moved, _ := s.GetBySlug(ctx, "top-boss"); moved == nil || moved.ID != "11-1011.00" {
+		t.Errorf("GetBySlug(top-boss) = %v, want 11-1011.00", moved)
+	}
+	if old, _ := s.GetBySlug(ctx, "chief-executive"); old != nil {
+		t.Errorf("GetBySlug(chief-executive) = %v after the rename, want nil", old.ID)
+	}
+}
+
+func TestMemoryStoreHonoursContext(t *testing.T) {
+	s := newTestStore(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	if _, err := s.GetByID(ctx, "11-1011.00"); !errors.Is(err, context.Canceled) {
+		t.Errorf("GetByID error = %v, want context.Canceled", err)
+	}
+	if _, err := s.Search(ctx, SearchOptions{Query: "manager", Limit: 10}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Search error = %v, want context.Canceled", err)
+	}
+	if err := s.Delete(ctx, "11-1011.00"); !errors.Is(err, context.Canceled) {
+		t.Errorf("Delete error = %v, want context.Canceled", err)
+	}
+	if occ, _ := s.GetByID(context.Background(), "11-1011.00"); occ == nil {
+		t.Error("Delete with a cancelled context removed the occupation")
+	}
+}
@@ -0,0 +1,104 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go-careers/models"
+)
+
+// GetMilitaryCodes returns the military occupation codes that crosswalk to an
+// occupation.
+func (r *OccupationRepository) GetMilitaryCodes(ctx context.Context, id string) ([]models.MilitaryCode, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first
+	cacheKey := fmt.Sprintf("mocs:%s", id)
+	var codes []models.MilitaryCode
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &codes); err == nil {
+			return codes, nil
+		}
+	}
+
+	// Cache miss - query database
+	rows, err := r.db.QueryContext(ctx, "SELECT moc_code FROM occupation_military WHERE occupation_id = ? ORDER BY moc_code", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	codes = []models.MilitaryCode{}
+	for rows.Next() {
+		var code string
+		if err := rows.Scan(&code); err != nil {
+			return nil, err
+		}
+		moc, err := models.ParseMOC(code)
+		if err != nil {
+			return nil, err
+		}
+		codes = append(codes, moc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, codes, time.Hour)
+	}
+
+	return codes, nil
+}
+
+// GetByMilitaryCode returns the civilian occupations a military occupation
+// code crosswalks to.
+func (r *OccupationRepository) GetByMilitaryCode(ctx context.Context, code string) ([]models.Occupation, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first
+	cacheKey := fmt.Sprintf("military:%s", code)
+	var occupations []models.Occupation
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &occupations); err == nil {
+			return occupations, nil
+		}
+	}
+
+	// Cache miss - query database
+	query := `
+		SELECT o.id, o.soc_id, o.soc_title, o.title, o.singular_title, o.description, o.typical_ed_level
+		FROM occupation_military m
+		JOIN occupations o ON o.id = m.occupation_id
+		WHERE m.moc_code = ?
+		ORDER BY o.title
+	`
+	rows, err := r.db.QueryContext(ctx, query, code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	occupations = []models.Occupation{}
+	for rows.Next() {
+		var occ models.Occupation
+		if err := rows.Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel); err != nil {
+			return nil, err
+		}
+		occupations = append(occupations, occ)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, occupations, time.Hour)
+	}
+
+	return occupations, nil
+}
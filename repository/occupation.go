@@ -1,60 +1,244 @@
 package repository
 
 import (
+	"context"
 	"database/sql"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
+	"github.com/go-sql-driver/mysql"
 	"go-careers/cache"
 	"go-careers/models"
 )
 
 type OccupationRepository struct {
-	db    *sql.DB
-	cache *cache.RedisCache
+	db       *sql.DB
+	cache    *cache.RedisCache
+	timeouts Timeouts
+
+	searchMu         sync.Mutex
+	snapshot         *searchSnapshot
+	snapshotVersion  int64
+	snapshotBuilt    time.Time
+	versionCheckedAt time.Time
+	versionChecking  bool
+}
+
+// Timeouts bounds each repository operation, including its cache lookups.
+// Read applies to lookups and searches, Write to creates, updates and
+// deletes. A zero value leaves the operation bounded only by its caller's
+// context.
+type Timeouts struct {
+	Read  time.Duration
+	Write time.Duration
 }
 
-func NewOccupationRepository(db *sql.DB, redisCache *cache.RedisCache) *OccupationRepository {
+func NewOccupationRepository(db *sql.DB, redisCache *cache.RedisCache, timeouts Timeouts) *OccupationRepository {
 	return &OccupationRepository{
-		db:    db,
-		cache: redisCache,
+		db:       db,
+		cache:    redisCache,
+		timeouts: timeouts,
+	}
+}
+
+// readContext derives the context of a read operation from ctx.
+func (r *OccupationRepository) readContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, r.timeouts.Read)
+}
+
+// writeContext derives the context of a write operation from ctx.
+func (r *OccupationRepository) writeContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return withTimeout(ctx, r.timeouts.Write)
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// sortColumns maps the sort names accepted by GetAll to their columns. Every
+// sort is tie-broken on id so the keyset cursor is always unique.
+var sortColumns = map[string]string{
+	"id":     "id",
+	"title":  "title",
+	"soc_id": "soc_id",
+}
+
+// ListOptions controls a page of GetAll results.
+type ListOptions struct {
+	Limit     int
+	Sort      string
+	Cursor    string
+	Education models.EducationFilter
+}
+
+// OccupationPage is one page of GetAll results. NextCursor is empty on the
+// last page.
+type OccupationPage struct {
+	Occupations []models.Occupation
+	Total       int
+	NextCursor  string
+}
+
+// pageCursor is the keyset position encoded into the opaque cursor string:
+// the sort column value and id of the last row on the previous page.
+type pageCursor struct {
+	Sort  string `json:"s"`
+	Value string `json:"v"`
+	ID    string `json:"id"`
+}
+
+// ErrInvalidCursor is returned by GetAll when the cursor cannot be decoded or
+// was issued for a different sort order.
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ErrNotFound is returned by writes to an occupation that does not exist.
+var ErrNotFound = errors.New("occupation not found")
+
+// ErrInvalidOccupation wraps the validation error of a rejected write.
+var ErrInvalidOccupation = errors.New("invalid occupation")
+
+func encodeCursor(c pageCursor) string {
+	b, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// sortValue returns the value occ is sorted on for a GetAll sort other than
+// id, and "" for id.
+func sortValue(sort string, occ models.Occupation) string {
+	switch sort {
+	case "title":
+		return occ.Title
+	case "soc_id":
+		return occ.SocID
 	}
+	return ""
 }
 
-func (r *OccupationRepository) GetAll() ([]models.Occupation, error) {
-	query := "SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level FROM occupations LIMIT 10"
-	rows, err := r.db.Query(query)
+func decodeCursor(s, sort string) (*pageCursor, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	var c pageCursor
+	if err := json.Unmarshal(b, &c); err != nil || c.Sort != sort {
+		return nil, ErrInvalidCursor
+	}
+	return &c, nil
+}
+
+func (r *OccupationRepository) GetAll(ctx context.Context, opts ListOptions) (*OccupationPage, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	if opts.Sort == "" {
+		opts.Sort = "id"
+	}
+	column, ok := sortColumns[opts.Sort]
+	if !ok {
+		return nil, fmt.Errorf("unknown sort: %s", opts.Sort)
+	}
+
+	// Filters shared by the count and the page query
+	var where []string
+	var filterArgs []interface{}
+	if !opts.Education.IsZero() {
+		labels := opts.Education.Labels()
+		if len(labels) == 0 {
+			return &OccupationPage{Occupations: []models.Occupation{}}, nil
+		}
+		where = append(where, "typical_ed_level IN (?"+strings.Repeat(",?", len(labels)-1)+")")
+		for _, label := range labels {
+			filterArgs = append(filterArgs, label)
+		}
+	}
+
+	page := &OccupationPage{Occupations: []models.Occupation{}}
+	countQuery := "SELECT COUNT(*) FROM occupations"
+	if len(where) > 0 {
+		countQuery += " WHERE " + strings.Join(where, " AND ")
+	}
+	if err := r.db.QueryRowContext(ctx, countQuery, filterArgs...).Scan(&page.Total); err != nil {
+		return nil, err
+	}
+
+	// Keyset pagination: fetch one extra row to learn whether a next page exists
+	query := "SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level FROM occupations"
+	args := filterArgs
+	if opts.Cursor != "" {
+		c, err := decodeCursor(opts.Cursor, opts.Sort)
+		if err != nil {
+			return nil, err
+		}
+		if column == "id" {
+			where = append(where, "id > ?")
+			args = append(args, c.ID)
+		} else {
+			where = append(where, fmt.Sprintf("(%[1]s > ? OR (%[1]s = ? AND id > ?))", column))
+			args = append(args, c.Value, c.Value, c.ID)
+		}
+	}
+	if len(where) > 0 {
+		query += " WHERE " + strings.Join(where, " AND ")
+	}
+	if column == "id" {
+		query += " ORDER BY id LIMIT ?"
+	} else {
+		query += fmt.Sprintf(" ORDER BY %s, id LIMIT ?", column)
+	}
+	args = append(args, opts.Limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	var occupations []models.Occupation
 	for rows.Next() {
 		var occ models.Occupation
 		if err := rows.Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel); err != nil {
 			return nil, err
 		}
-		occupations = append(occupations, occ)
+		page.Occupations = append(page.Occupations, occ)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	if len(page.Occupations) > opts.Limit {
+		page.Occupations = page.Occupations[:opts.Limit]
+		last := page.Occupations[opts.Limit-1]
+		page.NextCursor = encodeCursor(pageCursor{Sort: opts.Sort, Value: sortValue(opts.Sort, last), ID: last.ID})
 	}
 
-	return occupations, nil
+	return page, nil
 }
 
-func (r *OccupationRepository) GetByID(id string) (*models.Occupation, error) {
+func (r *OccupationRepository) GetByID(ctx context.Context, id string) (*models.Occupation, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
 	// Try cache first
 	cacheKey := fmt.Sprintf("occupation:%s", id)
 	var occ models.Occupation
 	if r.cache != nil {
-		if err := r.cache.Get(cacheKey, &occ); err == nil {
+		if err := r.cache.Get(ctx, cacheKey, &occ); err == nil {
 			return &occ, nil
 		}
 	}
 
 	// Cache miss - query database
-	query := "SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level FROM occupations WHERE id = ?"
-	err := r.db.QueryRow(query, id).Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel)
+	query := "SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level, JSON_EXTRACT(data, '$.educationAttainmentLevels') FROM occupations WHERE id = ?"
+	var attainment sql.NullString
+	err := r.db.QueryRowContext(ctx, query, id).Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel, &attainment)
 
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -62,115 +246,511 @@ func (r *OccupationRepository) GetByID(id string) (*models.Occupation, error) {
 		return nil, err
 	}
 
+	// The attainment distribution only lives in the data JSON
+	occ.EducationLevel = models.EducationLevelFromLabel(occ.TypicalEdLevel)
+	if attainment.Valid {
+		var levels []models.AttainmentLevel
+		if err := json.Unmarshal([]byte(attainment.String), &levels); err != nil {
+			return nil, err
+		}
+		occ.EducationDistribution = models.NewEducationDistribution(levels)
+	}
+
 	// Store in cache (1 hour TTL)
 	if r.cache != nil {
-		r.cache.Set(cacheKey, occ, time.Hour)
+		r.cache.Set(ctx, cacheKey, occ, time.Hour)
 	}
 
 	return &occ, nil
 }
 
-func (r *OccupationRepository) Search(searchTerm string) ([]models.Occupation, error) {
-	// Try cache first
-	cacheKey := fmt.Sprintf("search:%s", searchTerm)
-	var occupations []models.Occupation
-	if r.cache != nil {
-		if err := r.cache.Get(cacheKey, &occupations); err == nil {
-			return occupations, nil
+// BatchOptions controls CreateBatch. An atomic batch is written in a single
+// transaction and rolled back if any item fails; otherwise every valid item
+// is written on its own.
+type BatchOptions struct {
+	Mode   models.BatchMode
+	Atomic bool
+}
+
+// batchStatements holds the insert used for each batch mode. With the
+// driver's default affected-rows reporting, an upsert affects 1 row when it
+// inserts, 2 when it updates and 0 when the stored row already matched.
+var batchStatements = map[models.BatchMode]string{
+	models.BatchInsert:       "INSERT INTO occupations (" + recordColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
+	models.BatchSkipExisting: "INSERT INTO occupations (" + recordColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) ON DUPLICATE KEY UPDATE id = id",
+	models.BatchUpsert:       "INSERT INTO occupations (" + recordColumns + ") VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?) AS new ON DUPLICATE KEY UPDATE soc_id = new.soc_id, soc_title = new.soc_title, title = new.title, singular_title = new.singular_title, description = new.description, typical_ed_level = new.typical_ed_level, title_slug = new.title_slug, data = new.data",
+}
+
+// ErrBatchRolledBack is returned with the results of an atomic batch in
+// which some item failed. Nothing was written.
+var ErrBatchRolledBack = errors.New("batch rolled back")
+
+// CreateBatch writes occupation records, with their child rows, according to
+// opts and reports the outcome of each one. Items that fail validation or
+// are rejected by the database are reported as failed; other errors abort
+// the batch. A non-atomic batch that is aborted returns its results so far
+// along with the error, since the items before it stay written.
+func (r *OccupationRepository) CreateBatch(ctx context.Context, records []models.OccupationRecord, opts BatchOptions) ([]models.BatchResult, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+
+	results, failed := validateBatch(records)
+	if failed && opts.Atomic {
+		return rollBackResults(results), ErrBatchRolledBack
+	}
+
+	// Atomic batches share one transaction; otherwise each record is
+	// written in its own so a bad item cannot undo the others
+	var batchTx *sql.Tx
+	if opts.Atomic {
+		var err error
+		if batchTx, err = r.db.BeginTx(ctx, nil); err != nil {
+			return nil, err
 		}
+		defer batchTx.Rollback()
 	}
 
-	// Cache miss - query database
-	query := `
-		SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level
-		FROM occupations
-		WHERE title LIKE ? OR soc_title LIKE ?
-		LIMIT 50
-	`
-
-	searchPattern := "%" + searchTerm + "%"
-	rows, err := r.db.Query(query, searchPattern, searchPattern)
-	if err != nil {
-		return nil, err
+	var written []string
+	for i, rec := range records {
+		if results[i].Status == models.BatchFailed {
+			continue
+		}
+
+		tx := batchTx
+		if tx == nil {
+			var err error
+			if tx, err = r.db.BeginTx(ctx, nil); err != nil {
+				return r.abortBatch(ctx, results, i, written), err
+			}
+		}
+		status, err := writeBatchRecord(ctx, tx, rec, opts.Mode)
+		if err == nil && batchTx == nil {
+			err = tx.Commit()
+		}
+		if err != nil {
+			if batchTx == nil {
+				tx.Rollback()
+			}
+			var mysqlErr *mysql.MySQLError
+			if !errors.As(err, &mysqlErr) {
+				if opts.Atomic {
+					return nil, err
+				}
+				return r.abortBatch(ctx, results, i, written), err
+			}
+			results[i].Status = models.BatchFailed
+			results[i].Reason = mysqlErr.Message
+			if mysqlErr.Number == mysqlDuplicateEntry {
+				results[i].Reason = "occupation already exists"
+			}
+			// A failed statement can end the transaction, so an atomic
+			// batch stops at the first one
+			if opts.Atomic {
+				return rollBackResults(results), ErrBatchRolledBack
+			}
+			continue
+		}
+
+		results[i].Status = status
+		if status == models.BatchSkipped {
+			results[i].Reason = "occupation already exists"
+		} else {
+			written = append(written, rec.ID)
+		}
 	}
-	defer rows.Close()
 
-	occupations = []models.Occupation{}
-	for rows.Next() {
-		var occ models.Occupation
-		if err := rows.Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel); err != nil {
+	if batchTx != nil {
+		if err := batchTx.Commit(); err != nil {
 			return nil, err
 		}
-		occupations = append(occupations, occ)
 	}
 
-	// Store in cache (15 minutes TTL for searches)
-	if r.cache != nil {
-		r.cache.Set(cacheKey, occupations, 15*time.Minute)
+	if len(written) > 0 {
+		r.invalidateOccupations(ctx, written...)
+	}
+	return results, nil
+}
+
+// abortBatch stops a non-atomic batch at item from after an error that is
+// not specific to that item, such as a timeout or a lost connection. The
+// records already committed stay written, so their cache entries are
+// dropped, and every item not yet written is marked failed.
+func (r *OccupationRepository) abortBatch(ctx context.Context, results []models.BatchResult, from int, written []string) []models.BatchResult {
+	if len(written) > 0 {
+		r.invalidateOccupations(ctx, written...)
+	}
+	for i := from; i < len(results); i++ {
+		if results[i].Status == "" {
+			results[i].Status = models.BatchFailed
+			results[i].Reason = "not written: the batch was aborted"
+		}
 	}
+	return results
+}
 
-	return occupations, nil
+// writeBatchRecord writes one record of a batch and returns its outcome.
+func writeBatchRecord(ctx context.Context, tx *sql.Tx, rec models.OccupationRecord, mode models.BatchMode) (string, error) {
+	values, err := recordValues(rec)
+	if err != nil {
+		return "", err
+	}
+	result, err := tx.ExecContext(ctx, batchStatements[mode], values...)
+	if err != nil {
+		return "", err
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+
+	switch {
+	case n == 1:
+		return models.BatchCreated, insertChildren(ctx, tx, rec)
+	case mode == models.BatchUpsert:
+		return models.BatchUpdated, replaceChildren(ctx, tx, rec)
+	default:
+		return models.BatchSkipped, nil
+	}
+}
+
+// validateBatch returns a result for each record, marking those that fail
+// validation, and whether any did.
+func validateBatch(records []models.OccupationRecord) ([]models.BatchResult, bool) {
+	results := make([]models.BatchResult, len(records))
+	failed := false
+	for i := range records {
+		results[i] = models.BatchResult{Index: i, ID: records[i].ID}
+		if err := records[i].Validate(); err != nil {
+			results[i].Status = models.BatchFailed
+			results[i].Reason = err.Error()
+			failed = true
+		}
+	}
+	return results, failed
+}
+
+// mysqlDuplicateEntry is MySQL's ER_DUP_ENTRY error number.
+const mysqlDuplicateEntry = 1062
+
+// rollBackResults marks every item of a rolled back batch as failed, keeping
+// the reason of the items that caused it.
+func rollBackResults(results []models.BatchResult) []models.BatchResult {
+	for i := range results {
+		if results[i].Status != models.BatchFailed {
+			results[i].Status = models.BatchFailed
+			results[i].Reason = "not written: another item in the batch failed"
+		}
+	}
+	return results
 }
 
-func (r *OccupationRepository) CreateBatch(occupations []models.Occupation) error {
-	tx, err := r.db.Begin()
+// Update replaces an existing occupation with rec, including its child rows
+// and similarity lists. It returns ErrNotFound when no occupation has the
+// record's id.
+func (r *OccupationRepository) Update(ctx context.Context, rec models.OccupationRecord) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+
+	if err := rec.Validate(); err != nil {
+		return fmt.Errorf("%w: %s", ErrInvalidOccupation, err)
+	}
+	values, err := recordValues(rec)
+	if err != nil {
+		return err
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
 	defer tx.Rollback()
 
-	stmt, err := tx.Prepare("INSERT INTO occupations (id, soc_id, soc_title, title, singular_title, description, typical_ed_level) VALUES (?, ?, ?, ?, ?, ?, ?)")
+	if _, err := lockOccupation(ctx, tx, rec.ID); err != nil {
+		return err
+	}
+	// Move the id from the front of the values to the WHERE clause
+	args := append(values[1:], rec.ID)
+	if _, err := tx.ExecContext(ctx, "UPDATE occupations SET soc_id = ?, soc_title = ?, title = ?, singular_title = ?, description = ?, typical_ed_level = ?, title_slug = ?, data = ? WHERE id = ?", args...); err != nil {
+		return err
+	}
+	if err := replaceChildren(ctx, tx, rec); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+
+	r.invalidateOccupations(ctx, rec.ID)
+	return nil
+}
+
+// Patch applies a JSON Merge Patch (RFC 7396) to an existing occupation and
+// returns the result. The id cannot be changed. It returns ErrNotFound when
+// no occupation has the given id.
+func (r *OccupationRepository) Patch(ctx context.Context, id string, patch []byte) (*models.Occupation, error) {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	current, err := lockOccupation(ctx, tx, id)
+	if err != nil {
+		return nil, err
+	}
+	occ, err := applyPatch(*current, patch)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := updateOccupation(ctx, tx, occ, patchedSlug(*current, occ)); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	r.invalidateOccupations(ctx, id)
+	return &occ, nil
+}
+
+// patchMembers maps the members a patch may set to the JSON names of
+// Occupation. The camelCase names of the record format are accepted too.
+var patchMembers = map[string]string{
+	"id":               "id",
+	"soc_id":           "soc_id",
+	"socId":            "soc_id",
+	"soc_title":        "soc_title",
+	"socTitle":         "soc_title",
+	"title":            "title",
+	"singular_title":   "singular_title",
+	"singularTitle":    "singular_title",
+	"description":      "description",
+	"typical_ed_level": "typical_ed_level",
+	"typicalEdLevel":   "typical_ed_level",
+}
+
+// applyPatch returns current with a JSON Merge Patch applied, validated.
+// Patch keys are the members of patchMembers; any other is rejected rather
+// than ignored.
+func applyPatch(current models.Occupation, patch []byte) (models.Occupation, error) {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(patch, &raw); err != nil || raw == nil {
+		return models.Occupation{}, fmt.Errorf("%w: patch must be a JSON object", ErrInvalidOccupation)
+	}
+	changes := map[string]interface{}{}
+	for member, v := range raw {
+		name, ok := patchMembers[member]
+		if !ok {
+			return models.Occupation{}, fmt.Errorf("%w: unknown or read-only member %q", ErrInvalidOccupation, member)
+		}
+		if _, dup := changes[name]; dup {
+			return models.Occupation{}, fmt.Errorf("%w: %s is given more than once", ErrInvalidOccupation, name)
+		}
+		changes[name] = v
+	}
+
+	// Round-trip through the JSON representation so patch keys match the
+	// fields clients read
+	current.EducationLevel, current.EducationDistribution = "", nil
+	b, err := json.Marshal(current)
+	if err != nil {
+		return models.Occupation{}, err
+	}
+	var doc map[string]interface{}
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return models.Occupation{}, err
+	}
+	b, err = json.Marshal(mergePatch(doc, changes))
+	if err != nil {
+		return models.Occupation{}, err
+	}
+	var occ models.Occupation
+	if err := json.Unmarshal(b, &occ); err != nil {
+		return models.Occupation{}, fmt.Errorf("%w: %s", ErrInvalidOccupation, err)
+	}
+	occ.EducationLevel, occ.EducationDistribution = "", nil
+
+	if occ.ID != current.ID {
+		return models.Occupation{}, fmt.Errorf("%w: id cannot be changed", ErrInvalidOccupation)
+	}
+	if err := occ.Validate(); err != nil {
+		return models.Occupation{}, fmt.Errorf("%w: %s", ErrInvalidOccupation, err)
+	}
+	return occ, nil
+}
+
+// Delete removes an occupation together with its skills, tasks and other
+// child rows. It returns ErrNotFound when no occupation has the given id.
+func (r *OccupationRepository) Delete(ctx context.Context, id string) error {
+	ctx, cancel := r.writeContext(ctx)
+	defer cancel()
+
+	result, err := r.db.ExecContext(ctx, "DELETE FROM occupations WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	n, err := result.RowsAffected()
 	if err != nil {
 		return err
 	}
-	defer stmt.Close()
+	if n == 0 {
+		return ErrNotFound
+	}
 
-	for _, occ := range occupations {
-		_, err := stmt.Exec(occ.ID, occ.SocID, occ.SocTitle, occ.Title, occ.SingularTitle, occ.Description, occ.TypicalEdLevel)
-		if err != nil {
-			return err
+	r.invalidateOccupations(ctx, id)
+	return nil
+}
+
+// lockOccupation reads an occupation's stored fields and locks its row for
+// the rest of the transaction.
+func lockOccupation(ctx context.Context, tx *sql.Tx, id string) (*models.Occupation, error) {
+	var occ models.Occupation
+	err := tx.QueryRowContext(ctx, "SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level FROM occupations WHERE id = ? FOR UPDATE", id).
+		Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel)
+	if err == sql.ErrNoRows {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &occ, nil
+}
+
+// patchedSlug returns the slug an occupation moves to when a patch renames
+// it, following the singular title if that changed and the title otherwise,
+// or "" when neither changed.
+func patchedSlug(current, occ models.Occupation) string {
+	switch {
+	case occ.SingularTitle != current.SingularTitle && occ.SingularTitle != "":
+		return models.Slugify(occ.SingularTitle)
+	case occ.Title != current.Title:
+		return models.Slugify(occ.Title)
+	}
+	return ""
+}
+
+// updateOccupation writes the core fields of occ, and its slug unless slug
+// is "", keeping the copies in the data JSON in step.
+func updateOccupation(ctx context.Context, tx *sql.Tx, occ models.Occupation, slug string) error {
+	_, err := tx.ExecContext(ctx, `UPDATE occupations SET soc_id = ?, soc_title = ?, title = ?, singular_title = ?, description = ?, typical_ed_level = ?,
+		data = JSON_SET(COALESCE(data, JSON_OBJECT()), '$.socId', ?, '$.socTitle', ?, '$.title', ?, '$.singularTitle', ?, '$.description', ?, '$.typicalEdLevel', ?)
+		WHERE id = ?`,
+		occ.SocID, occ.SocTitle, occ.Title, occ.SingularTitle, occ.Description, occ.TypicalEdLevel,
+		occ.SocID, occ.SocTitle, occ.Title, occ.SingularTitle, occ.Description, occ.TypicalEdLevel,
+		occ.ID)
+	if err != nil || slug == "" {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, "UPDATE occupations SET title_slug = ?, data = JSON_SET(COALESCE(data, JSON_OBJECT()), '$.titleSlug', ?) WHERE id = ?",
+		slug, slug, occ.ID)
+	return err
+}
+
+// mergePatch applies an RFC 7396 merge patch to target: objects are merged
+// recursively, null removes a member and any other value replaces it.
+func mergePatch(target, patch interface{}) interface{} {
+	changes, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	doc, ok := target.(map[string]interface{})
+	if !ok {
+		doc = map[string]interface{}{}
+	}
+	for k, v := range changes {
+		if v == nil {
+			delete(doc, k)
+		} else {
+			doc[k] = mergePatch(doc[k], v)
 		}
 	}
+	return doc
+}
+
+// invalidateOccupations drops every cached value that may include the given
+// occupations: their own entries, and the lists, groupings and searches any
+// occupation can appear in.
+func (r *OccupationRepository) invalidateOccupations(ctx context.Context, ids ...string) {
+	// The write has already committed, so invalidation must not be cut
+	// short by the caller's deadline; each cache call has its own
+	ctx = context.WithoutCancel(ctx)
 
-	return tx.Commit()
+	r.invalidateSearch(ctx)
+	if r.cache == nil {
+		return
+	}
+
+	for _, id := range ids {
+		r.cache.Delete(ctx, fmt.Sprintf("occupation:%s", id))
+		r.cache.Delete(ctx, fmt.Sprintf("tasks:%s", id))
+		r.cache.Delete(ctx, fmt.Sprintf("mocs:%s", id))
+		for kind := range competencyColumns {
+			r.cache.Delete(ctx, fmt.Sprintf("%s:%s", kind, id))
+		}
+	}
+	for _, pattern := range []string{"similar:*", "tasksearch:*", "military:*", "soc:*", "socgroups:*", "clusters:*", "interests:*"} {
+		r.cache.DeletePattern(ctx, pattern)
+	}
 }
 
-func (r *OccupationRepository) GetSimilar(id string) ([]models.Occupation, error) {
+// GetSimilar returns the occupations listed as similar to id by the
+// similarity source named by by (see models.SimilarityLists), or by every
+// source when by is models.SimilarAll. Each source's ranking is preserved;
+// when sources are combined, occupations are ordered by their best rank in
+// any list, then by how many lists they appear in.
+func (r *OccupationRepository) GetSimilar(ctx context.Context, id, by string) ([]models.SimilarOccupation, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
 	// Try cache first
-	cacheKey := fmt.Sprintf("similar:%s", id)
-	var occupations []models.Occupation
+	cacheKey := fmt.Sprintf("similar:%s:%s", by, id)
+	var similar []models.SimilarOccupation
 	if r.cache != nil {
-		if err := r.cache.Get(cacheKey, &occupations); err == nil {
-			return occupations, nil
+		if err := r.cache.Get(ctx, cacheKey, &similar); err == nil {
+			return similar, nil
 		}
 	}
 
 	// Cache miss - query database
 	// First, get the data JSON for the occupation
-	var dataJSON string
-	err := r.db.QueryRow("SELECT data FROM occupations WHERE id = ?", id).Scan(&dataJSON)
-	if err == sql.ErrNoRows {
-		return []models.Occupation{}, nil
+	var dataJSON sql.NullString
+	err := r.db.QueryRowContext(ctx, "SELECT data FROM occupations WHERE id = ?", id).Scan(&dataJSON)
+	if err == sql.ErrNoRows || (err == nil && !dataJSON.Valid) {
+		return []models.SimilarOccupation{}, nil
 	} else if err != nil {
 		return nil, err
 	}
 
-	// Parse JSON to extract similarOccs array
-	var data struct {
-		SimilarOccs []string `json:"similarOccs"`
-	}
-	if err := json.Unmarshal([]byte(dataJSON), &data); err != nil {
+	// Parse JSON to extract the similarity lists
+	var data map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(dataJSON.String), &data); err != nil {
 		return nil, err
 	}
 
-	if len(data.SimilarOccs) == 0 {
-		return []models.Occupation{}, nil
+	lists := map[string][]string{}
+	for _, source := range models.SimilaritySources {
+		var list []string
+		if raw, ok := data[models.SimilarityLists[source]]; ok {
+			if err := json.Unmarshal(raw, &list); err != nil {
+				return nil, err
+			}
+		}
+		lists[source] = list
+	}
+	ids, sources := rankSimilar(lists, by)
+
+	if len(ids) == 0 {
+		return []models.SimilarOccupation{}, nil
 	}
 
 	// Build query with placeholders for each similar occupation ID
 	query := "SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level FROM occupations WHERE id IN ("
-	args := make([]interface{}, len(data.SimilarOccs))
-	for i, similarID := range data.SimilarOccs {
+	args := make([]interface{}, len(ids))
+	for i, similarID := range ids {
 		if i > 0 {
 			query += ","
 		}
@@ -179,26 +759,73 @@ func (r *OccupationRepository) GetSimilar(id string) ([]models.Occupation, error
 	}
 	query += ")"
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	// Initialize as empty slice so it returns [] instead of null when empty
-	occupations = []models.Occupation{}
+	found := map[string]models.Occupation{}
 	for rows.Next() {
 		var occ models.Occupation
 		if err := rows.Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel); err != nil {
 			return nil, err
 		}
-		occupations = append(occupations, occ)
+		found[occ.ID] = occ
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Listed occupations missing from the database are skipped
+	similar = []models.SimilarOccupation{}
+	for _, similarID := range ids {
+		occ, ok := found[similarID]
+		if !ok {
+			continue
+		}
+		similar = append(similar, models.SimilarOccupation{
+			Occupation: occ,
+			Sources:    sources[similarID],
+		})
 	}
 
 	// Store in cache (1 hour TTL)
 	if r.cache != nil {
-		r.cache.Set(cacheKey, occupations, time.Hour)
+		r.cache.Set(ctx, cacheKey, similar, time.Hour)
 	}
 
-	return occupations, nil
+	return similar, nil
+}
+
+// rankSimilar combines the similarity lists, keyed by source, selected by by
+// into one ranking of occupation ids and the sources listing each. Each
+// source's ranking is preserved; combined occupations are ordered by their
+// best rank in any list, then by how many lists they appear in.
+func rankSimilar(lists map[string][]string, by string) ([]string, map[string][]string) {
+	best := map[string]int{}
+	sources := map[string][]string{}
+	var ids []string
+	for _, source := range models.SimilaritySources {
+		if by != models.SimilarAll && by != source {
+			continue
+		}
+		for rank, id := range lists[source] {
+			if _, ok := best[id]; !ok {
+				best[id] = rank
+				ids = append(ids, id)
+			}
+			best[id] = min(best[id], rank)
+			sources[id] = append(sources[id], source)
+		}
+	}
+
+	sort.SliceStable(ids, func(i, j int) bool {
+		a, b := ids[i], ids[j]
+		if best[a] != best[b] {
+			return best[a] < best[b]
+		}
+		return len(sources[a]) > len(sources[b])
+	})
+	return ids, sources
 }
@@ -0,0 +1,133 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"go-careers/models"
+)
+
+// childTables are the tables holding an occupation's child rows, as filled by
+// the seed converter.
+var childTables = []string{
+	"occupation_tasks",
+	"occupation_alt_titles",
+	"occupation_military",
+	"occupation_pathways",
+	"occupation_skills",
+	"occupation_knowledge",
+	"occupation_abilities",
+}
+
+// recordColumns are the occupations columns written from a record, in the
+// order recordValues returns them.
+const recordColumns = "id, soc_id, soc_title, title, singular_title, description, typical_ed_level, title_slug, data"
+
+// recordValues returns the occupations row for rec. The data column keeps
+// the whole record, as the seed does, for the fields only read from JSON.
+func recordValues(rec models.OccupationRecord) ([]interface{}, error) {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return nil, err
+	}
+	return []interface{}{rec.ID, rec.SocID, rec.SocTitle, rec.Title, rec.SingularTitle, rec.Description, rec.TypicalEdLevel, rec.Slug(), string(data)}, nil
+}
+
+// replaceChildren deletes an occupation's child rows and writes those of rec.
+func replaceChildren(ctx context.Context, tx *sql.Tx, rec models.OccupationRecord) error {
+	for _, table := range childTables {
+		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s WHERE occupation_id = ?", table), rec.ID); err != nil {
+			return err
+		}
+	}
+	return insertChildren(ctx, tx, rec)
+}
+
+// insertChildren writes the tasks, alternate titles, military codes,
+// pathways and competencies of rec into the same tables the seed SQL fills.
+// rec must have been validated.
+func insertChildren(ctx context.Context, tx *sql.Tx, rec models.OccupationRecord) error {
+	var tasks [][]interface{}
+	for _, task := range rec.CoreTasks {
+		tasks = append(tasks, []interface{}{rec.ID, task})
+	}
+	if err := insertRows(ctx, tx, "occupation_tasks", "occupation_id, task", tasks); err != nil {
+		return err
+	}
+
+	var titles [][]interface{}
+	for _, title := range rec.LayTitles {
+		titles = append(titles, []interface{}{rec.ID, title, models.AltTitleLay})
+	}
+	for _, title := range rec.EmsiTitles {
+		titles = append(titles, []interface{}{rec.ID, title, models.AltTitleEmsi})
+	}
+	if err := insertRows(ctx, tx, "occupation_alt_titles", "occupation_id, title, source", titles); err != nil {
+		return err
+	}
+
+	var mocs [][]interface{}
+	for _, code := range rec.Mocs {
+		moc, err := models.ParseMOC(code)
+		if err != nil {
+			return err
+		}
+		mocs = append(mocs, []interface{}{rec.ID, moc.Code, moc.Branch, moc.Category})
+	}
+	if err := insertRows(ctx, tx, "occupation_military", "occupation_id, moc_code, branch, category", mocs); err != nil {
+		return err
+	}
+
+	// Pathways missing from the lookup table are added without a name, as
+	// the seed converter does
+	var pathways [][]interface{}
+	for _, pathway := range rec.Pathways {
+		clusterID, _, _ := strings.Cut(pathway, ".")
+		if _, err := tx.ExecContext(ctx, "INSERT IGNORE INTO career_pathways (id, cluster_id) VALUES (?, ?)", pathway, clusterID); err != nil {
+			return err
+		}
+		pathways = append(pathways, []interface{}{rec.ID, pathway})
+	}
+	if err := insertRows(ctx, tx, "occupation_pathways", "occupation_id, pathway_id", pathways); err != nil {
+		return err
+	}
+
+	for kind, competencies := range map[models.CompetencyType][]models.CompetencyRecord{
+		models.CompetencySkills:    rec.Skills,
+		models.CompetencyKnowledge: rec.Knowledge,
+		models.CompetencyAbilities: rec.Abilities,
+	} {
+		var rows [][]interface{}
+		for _, c := range competencies {
+			rows = append(rows, []interface{}{rec.ID, c.Name, c.Description, float64(c.Importance), float64(c.Level)})
+		}
+		cols := competencyColumns[kind]
+		columns := fmt.Sprintf("occupation_id, %[1]s_name, %[1]s_description, importance, level", cols.prefix)
+		if err := insertRows(ctx, tx, cols.table, columns, rows); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// insertRows writes rows into table with a single multi-row INSERT.
+func insertRows(ctx context.Context, tx *sql.Tx, table, columns string, rows [][]interface{}) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	placeholders := "(?" + strings.Repeat(", ?", len(rows[0])-1) + ")"
+	values := make([]string, len(rows))
+	args := make([]interface{}, 0, len(rows)*len(rows[0]))
+	for i, row := range rows {
+		values[i] = placeholders
+		args = append(args, row...)
+	}
+
+	_, err := tx.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s (%s) VALUES %s", table, columns, strings.Join(values, ", ")), args...)
+	return err
+}
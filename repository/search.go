@@ -0,0 +1,356 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"go-careers/models"
+	"go-careers/search"
+)
+
+// searchWeights sets how much a match in each field counts towards an
+// occupation's relevance score.
+var searchWeights = map[string]float64{
+	"title":       3,
+	"soc_title":   2,
+	"lay_titles":  2,
+	"emsi_titles": 1.5,
+	"description": 1,
+}
+
+// altTitleFields maps the index fields holding alternate titles to their
+// source in occupation_alt_titles.
+var altTitleFields = map[string]string{
+	"lay_titles":  models.AltTitleLay,
+	"emsi_titles": models.AltTitleEmsi,
+}
+
+// autocompleteFields are the document fields offered as typeahead
+// suggestions, in priority order.
+var autocompleteFields = []string{"title", "singular_title", "short_title", "lay_titles"}
+
+// fuzzyFields are the document fields matched by fuzzy searches.
+var fuzzyFields = []string{"title", "lay_titles"}
+
+// searchSnapshot is the in-process search, typeahead and fuzzy indexes
+// together with the occupations they were built from, so hits can be
+// returned without a query.
+type searchSnapshot struct {
+	index       *search.Index
+	prefix      *search.PrefixIndex
+	trigrams    *search.TrigramIndex
+	occupations map[string]models.Occupation
+}
+
+// SearchOptions controls a page of Search results. Fuzzy switches from
+// relevance search to trigram similarity over titles and lay titles, which
+// tolerates misspellings.
+type SearchOptions struct {
+	Query     string
+	Limit     int
+	Offset    int
+	Fuzzy     bool
+	Education models.EducationFilter
+}
+
+// maxFuzzyResults caps how many fuzzy matches are ranked per query.
+const maxFuzzyResults = 100
+
+// SearchPage is one page of Search results. Total counts every match.
+// DidYouMean holds a spelling correction of the query when the relevance
+// search finds nothing.
+type SearchPage struct {
+	Total      int                   `json:"total"`
+	Results    []models.SearchResult `json:"results"`
+	DidYouMean string                `json:"did_you_mean,omitempty"`
+}
+
+const (
+	// searchVersionKey counts writes across every instance sharing the
+	// cache, so each one knows when to rebuild its index. It lies outside
+	// search:* so invalidateSearch does not delete it.
+	searchVersionKey = "searchindex:version"
+	// searchSnapshotTTL bounds how stale an index can get when the version
+	// cannot be read, such as without Redis.
+	searchSnapshotTTL = 5 * time.Minute
+	// searchVersionCheckInterval is how often a built index checks the
+	// shared version. The check runs in the background, so searches and
+	// typeahead never wait on Redis for it.
+	searchVersionCheckInterval = time.Second
+)
+
+// searchVersion returns the shared search index version, and false when
+// there is none to read.
+func (r *OccupationRepository) searchVersion(ctx context.Context) (int64, bool) {
+	if r.cache == nil {
+		return 0, false
+	}
+	version, err := r.cache.Counter(ctx, searchVersionKey)
+	if err != nil {
+		return 0, false
+	}
+	return version, true
+}
+
+// loadSearchSnapshot returns the current search index, building it from the
+// database on first use, after invalidateSearch, once another instance has
+// bumped the shared version and once it is searchSnapshotTTL old. A built
+// index is served straight away while checkSearchVersion looks at the
+// shared version in the background.
+func (r *OccupationRepository) loadSearchSnapshot(ctx context.Context) (*searchSnapshot, error) {
+	r.searchMu.Lock()
+	defer r.searchMu.Unlock()
+
+	if r.snapshot != nil && time.Since(r.snapshotBuilt) < searchSnapshotTTL {
+		r.checkSearchVersion()
+		return r.snapshot, nil
+	}
+
+	// Read the version before building, so a write made during the build
+	// triggers another one
+	version, _ := r.searchVersion(ctx)
+
+	altTitles, err := r.loadAltTitles(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := r.db.QueryContext(ctx, "SELECT id, soc_id, soc_title, title, singular_title, description, typical_ed_level, JSON_UNQUOTE(JSON_EXTRACT(data, '$.shortTitle')) FROM occupations")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []searchEntry
+	for rows.Next() {
+		var e searchEntry
+		var shortTitle sql.NullString
+		if err := rows.Scan(&e.occupation.ID, &e.occupation.SocID, &e.occupation.SocTitle, &e.occupation.Title, &e.occupation.SingularTitle, &e.occupation.Description, &e.occupation.TypicalEdLevel, &shortTitle); err != nil {
+			return nil, err
+		}
+		e.shortTitle = shortTitle.String
+		e.layTitles = altTitles[e.occupation.ID][models.AltTitleLay]
+		e.emsiTitles = altTitles[e.occupation.ID][models.AltTitleEmsi]
+		entries = append(entries, e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	r.snapshot = newSearchSnapshot(entries)
+	r.snapshotVersion, r.snapshotBuilt = version, time.Now()
+	r.versionCheckedAt = r.snapshotBuilt
+	return r.snapshot, nil
+}
+
+// checkSearchVersion starts a background read of the shared version, at most
+// once per searchVersionCheckInterval, and drops the index if another
+// instance has bumped it since the index was built. The caller must hold
+// searchMu.
+func (r *OccupationRepository) checkSearchVersion() {
+	if r.cache == nil || r.versionChecking || time.Since(r.versionCheckedAt) < searchVersionCheckInterval {
+		return
+	}
+	r.versionChecking = true
+
+	go func() {
+		version, versioned := r.searchVersion(context.Background())
+
+		r.searchMu.Lock()
+		defer r.searchMu.Unlock()
+		r.versionChecking = false
+		r.versionCheckedAt = time.Now()
+		if versioned && r.snapshot != nil && version != r.snapshotVersion {
+			r.snapshot = nil
+		}
+	}()
+}
+
+// searchEntry is an occupation with the extra titles it is searchable by.
+type searchEntry struct {
+	occupation models.Occupation
+	shortTitle string
+	layTitles  []string
+	emsiTitles []string
+}
+
+// newSearchSnapshot indexes entries for search, typeahead and fuzzy matching.
+func newSearchSnapshot(entries []searchEntry) *searchSnapshot {
+	snapshot := &searchSnapshot{occupations: map[string]models.Occupation{}}
+	docs := make([]search.Document, 0, len(entries))
+	for _, e := range entries {
+		occ := e.occupation
+		snapshot.occupations[occ.ID] = occ
+		docs = append(docs, search.Document{
+			ID: occ.ID,
+			Fields: map[string][]string{
+				"title":          {occ.Title},
+				"singular_title": {occ.SingularTitle},
+				"short_title":    nonEmpty(e.shortTitle),
+				"soc_title":      {occ.SocTitle},
+				"description":    {occ.Description},
+				"lay_titles":     e.layTitles,
+				"emsi_titles":    e.emsiTitles,
+			},
+		})
+	}
+
+	snapshot.index = search.NewIndex(docs, searchWeights)
+	snapshot.prefix = search.NewPrefixIndex(docs, autocompleteFields)
+	snapshot.trigrams = search.NewTrigramIndex(docs, fuzzyFields)
+	return snapshot
+}
+
+// loadAltTitles returns every alternate title keyed by occupation id and then
+// by source.
+func (r *OccupationRepository) loadAltTitles(ctx context.Context) (map[string]map[string][]string, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT occupation_id, title, source FROM occupation_alt_titles ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	altTitles := map[string]map[string][]string{}
+	for rows.Next() {
+		var occupationID, title, source string
+		if err := rows.Scan(&occupationID, &title, &source); err != nil {
+			return nil, err
+		}
+		if altTitles[occupationID] == nil {
+			altTitles[occupationID] = map[string][]string{}
+		}
+		altTitles[occupationID][source] = append(altTitles[occupationID][source], title)
+	}
+
+	return altTitles, rows.Err()
+}
+
+// nonEmpty wraps s in a slice, or returns nil when s is empty.
+func nonEmpty(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return []string{s}
+}
+
+// invalidateSearch drops the search index and cached search results so the
+// next search sees the latest occupations, and bumps the shared version so
+// other instances rebuild their indexes too.
+func (r *OccupationRepository) invalidateSearch(ctx context.Context) {
+	r.searchMu.Lock()
+	r.snapshot = nil
+	r.searchMu.Unlock()
+
+	if r.cache != nil {
+		r.cache.Incr(ctx, searchVersionKey)
+		r.cache.DeletePattern(ctx, "search:*")
+	}
+}
+
+func (r *OccupationRepository) Search(ctx context.Context, opts SearchOptions) (*SearchPage, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first; results are keyed by index version so none built
+	// from an older index are served
+	version, _ := r.searchVersion(ctx)
+	cacheKey := fmt.Sprintf("search:%d:%t:%s:%s:%d:%d:%s", version, opts.Fuzzy, opts.Education.Level, opts.Education.Max, opts.Limit, opts.Offset, opts.Query)
+	var page SearchPage
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &page); err == nil {
+			return &page, nil
+		}
+	}
+
+	// Cache miss - query the search index
+	snapshot, err := r.loadSearchSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	page = snapshot.search(opts)
+
+	// Store in cache (15 minutes TTL for searches)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, page, 15*time.Minute)
+	}
+
+	return &page, nil
+}
+
+// Autocomplete returns up to limit typeahead suggestions for a title prefix
+// across titles, singular and short titles, and lay titles.
+func (r *OccupationRepository) Autocomplete(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	snapshot, err := r.loadSearchSnapshot(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return snapshot.autocomplete(prefix, limit), nil
+}
+
+// search runs a relevance or fuzzy search and returns the requested page.
+func (s *searchSnapshot) search(opts SearchOptions) SearchPage {
+	var page SearchPage
+	hits := s.index.Search(opts.Query)
+	if len(hits) == 0 {
+		page.DidYouMean = s.index.Suggest(opts.Query)
+	}
+
+	results := make([]models.SearchResult, 0, len(hits))
+	if opts.Fuzzy {
+		for _, hit := range s.trigrams.Search(opts.Query, maxFuzzyResults) {
+			results = append(results, models.SearchResult{
+				Occupation:         s.occupations[hit.ID],
+				Score:              hit.Similarity,
+				MatchedTitle:       hit.Value,
+				MatchedTitleSource: altTitleFields[hit.Field],
+			})
+		}
+	} else {
+		for _, hit := range hits {
+			result := models.SearchResult{
+				Occupation: s.occupations[hit.ID],
+				Score:      hit.Score,
+			}
+			for _, m := range hit.Matches {
+				if source, ok := altTitleFields[m.Field]; ok {
+					result.MatchedTitle = m.Value
+					result.MatchedTitleSource = source
+					break
+				}
+			}
+			results = append(results, result)
+		}
+	}
+
+	if !opts.Education.IsZero() {
+		filtered := results[:0]
+		for _, result := range results {
+			if opts.Education.Matches(result.TypicalEdLevel) {
+				filtered = append(filtered, result)
+			}
+		}
+		results = filtered
+	}
+
+	page.Total = len(results)
+	page.Results = results[min(opts.Offset, len(results)):min(opts.Offset+opts.Limit, len(results))]
+	return page
+}
+
+func (s *searchSnapshot) autocomplete(prefix string, limit int) []models.Suggestion {
+	suggestions := []models.Suggestion{}
+	for _, sg := range s.prefix.Lookup(prefix, limit) {
+		suggestions = append(suggestions, models.Suggestion{
+			Text:         sg.Value,
+			Field:        sg.Field,
+			OccupationID: sg.ID,
+			Title:        s.occupations[sg.ID].Title,
+		})
+	}
+	return suggestions
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+
+	"go-careers/models"
+)
+
+// OccupationStore is the occupation storage the handlers work against.
+// OccupationRepository keeps occupations in MySQL, optionally cached in
+// Redis; MemoryStore keeps them in process, loaded from a JSONL export.
+//
+// Every method honours the deadline and cancellation of ctx. MemoryStore
+// never waits on I/O, so it only checks ctx before starting.
+//
+// Lookups of a single occupation return nil when it does not exist; writes
+// return ErrNotFound.
+type OccupationStore interface {
+	GetAll(ctx context.Context, opts ListOptions) (*OccupationPage, error)
+	GetByID(ctx context.Context, id string) (*models.Occupation, error)
+	GetBySlug(ctx context.Context, slug string) (*models.Occupation, error)
+	GetBySocID(ctx context.Context, socID string) ([]models.Occupation, error)
+	GetSocCodes(ctx context.Context) ([]models.SocCode, error)
+	GetSimilar(ctx context.Context, id, by string) ([]models.SimilarOccupation, error)
+	GetCompetencies(ctx context.Context, id string, kind models.CompetencyType) ([]models.Competency, error)
+	GetTasks(ctx context.Context, id string) ([]models.Task, error)
+	GetMilitaryCodes(ctx context.Context, id string) ([]models.MilitaryCode, error)
+	GetByMilitaryCode(ctx context.Context, code string) ([]models.Occupation, error)
+
+	GetClusters(ctx context.Context) ([]models.CareerCluster, error)
+	GetPathways(ctx context.Context, clusterID int) ([]models.CareerPathway, error)
+	GetPathway(ctx context.Context, id string) (*models.CareerPathway, error)
+	GetPathwayOccupations(ctx context.Context, id string) ([]models.Occupation, error)
+
+	Search(ctx context.Context, opts SearchOptions) (*SearchPage, error)
+	Autocomplete(ctx context.Context, prefix string, limit int) ([]models.Suggestion, error)
+	SearchTasks(ctx context.Context, searchTerm string, limit int) ([]models.TaskMatch, error)
+	MatchInterests(ctx context.Context, profile models.InterestProfile, method string, limit int) ([]models.InterestMatch, error)
+
+	CreateBatch(ctx context.Context, records []models.OccupationRecord, opts BatchOptions) ([]models.BatchResult, error)
+	Update(ctx context.Context, rec models.OccupationRecord) error
+	Patch(ctx context.Context, id string, patch []byte) (*models.Occupation, error)
+	Delete(ctx context.Context, id string) error
+}
+
+var (
+	_ OccupationStore = (*OccupationRepository)(nil)
+	_ OccupationStore = (*MemoryStore)(nil)
+)
@@ -0,0 +1,131 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"go-careers/models"
+)
+
+func (r *OccupationRepository) GetTasks(ctx context.Context, id string) ([]models.Task, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first
+	cacheKey := fmt.Sprintf("tasks:%s", id)
+	var tasks []models.Task
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &tasks); err == nil {
+			return tasks, nil
+		}
+	}
+
+	// Cache miss - query database
+	rows, err := r.db.QueryContext(ctx, "SELECT id, task FROM occupation_tasks WHERE occupation_id = ? ORDER BY id", id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tasks = []models.Task{}
+	for rows.Next() {
+		var t models.Task
+		if err := rows.Scan(&t.ID, &t.Task); err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, t)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// Store in cache (1 hour TTL)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, tasks, time.Hour)
+	}
+
+	return tasks, nil
+}
+
+// maxMatchedTasks caps how many matching tasks are reported per occupation in
+// task search results.
+const maxMatchedTasks = 3
+
+// SearchTasks finds occupations whose core tasks match the search term, using
+// the FULLTEXT index on occupation_tasks. Occupations are ranked by the summed
+// relevance of their matching tasks.
+func (r *OccupationRepository) SearchTasks(ctx context.Context, searchTerm string, limit int) ([]models.TaskMatch, error) {
+	ctx, cancel := r.readContext(ctx)
+	defer cancel()
+
+	// Try cache first
+	cacheKey := fmt.Sprintf("tasksearch:%d:%s", limit, searchTerm)
+	var matches []models.TaskMatch
+	if r.cache != nil {
+		if err := r.cache.Get(ctx, cacheKey, &matches); err == nil {
+			return matches, nil
+		}
+	}
+
+	// Cache miss - query database
+	query := `
+		SELECT o.id, o.soc_id, o.soc_title, o.title, o.singular_title, o.description, o.typical_ed_level,
+			t.task, MATCH(t.task) AGAINST (? IN NATURAL LANGUAGE MODE) AS score
+		FROM occupation_tasks t
+		JOIN occupations o ON o.id = t.occupation_id
+		WHERE MATCH(t.task) AGAINST (? IN NATURAL LANGUAGE MODE)
+		ORDER BY score DESC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, searchTerm, searchTerm)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// Group matching tasks by occupation, keeping the best tasks first
+	byID := map[string]*models.TaskMatch{}
+	var order []string
+	for rows.Next() {
+		var occ models.Occupation
+		var task string
+		var score float64
+		if err := rows.Scan(&occ.ID, &occ.SocID, &occ.SocTitle, &occ.Title, &occ.SingularTitle, &occ.Description, &occ.TypicalEdLevel, &task, &score); err != nil {
+			return nil, err
+		}
+
+		m, ok := byID[occ.ID]
+		if !ok {
+			m = &models.TaskMatch{Occupation: occ, MatchedTasks: []string{}}
+			byID[occ.ID] = m
+			order = append(order, occ.ID)
+		}
+		m.Score += score
+		if len(m.MatchedTasks) < maxMatchedTasks {
+			m.MatchedTasks = append(m.MatchedTasks, task)
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	matches = make([]models.TaskMatch, 0, len(order))
+	for _, id := range order {
+		matches = append(matches, *byID[id])
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	// Store in cache (15 minutes TTL for searches)
+	if r.cache != nil {
+		r.cache.Set(ctx, cacheKey, matches, 15*time.Minute)
+	}
+
+	return matches, nil
+}
@@ -0,0 +1,219 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// FuzzyHit is a document whose field value is similar to a query, scored
+// between 0 and 1.
+type FuzzyHit struct {
+	ID         string
+	Field      string
+	Value      string
+	Similarity float64
+}
+
+type trigramPosting struct {
+	doc   int
+	field int
+	value int
+}
+
+// TrigramIndex is an immutable index of field values by their character
+// trigrams, used to match misspelled queries.
+type TrigramIndex struct {
+	docs     []Document
+	fields   []string
+	sizes    map[trigramPosting]int
+	postings map[string][]trigramPosting
+}
+
+// minSimilarity is the lowest similarity FuzzySearch reports.
+const minSimilarity = 0.45
+
+// NewTrigramIndex indexes the given fields of docs.
+func NewTrigramIndex(docs []Document, fields []string) *TrigramIndex {
+	tx := &TrigramIndex{
+		docs:     docs,
+		fields:   fields,
+		sizes:    map[trigramPosting]int{},
+		postings: map[string][]trigramPosting{},
+	}
+
+	for di, doc := range docs {
+		for fi, field := range fields {
+			for vi, v := range doc.Fields[field] {
+				p := trigramPosting{doc: di, field: fi, value: vi}
+				grams := trigrams(v)
+				tx.sizes[p] = len(grams)
+				for g := range grams {
+					tx.postings[g] = append(tx.postings[g], p)
+				}
+			}
+		}
+	}
+
+	return tx
+}
+
+// Search returns up to limit documents with a value similar to query, most
+// similar first, reporting each document's best value. Similarity mostly
+// measures how many of the query's trigrams appear in the value, so "enginer"
+// still finds "Architectural and Engineering Managers", with a smaller share
+// from overall overlap so closer-length values win ties.
+func (tx *TrigramIndex) Search(query string, limit int) []FuzzyHit {
+	q := trigrams(query)
+	if len(q) == 0 {
+		return []FuzzyHit{}
+	}
+
+	shared := map[trigramPosting]int{}
+	for g := range q {
+		for _, p := range tx.postings[g] {
+			shared[p]++
+		}
+	}
+
+	best := map[int]FuzzyHit{}
+	for p, n := range shared {
+		containment := float64(n) / float64(len(q))
+		jaccard := float64(n) / float64(len(q)+tx.sizes[p]-n)
+		similarity := math.Round((0.8*containment+0.2*jaccard)*1000) / 1000
+		if similarity < minSimilarity {
+			continue
+		}
+
+		field := tx.fields[p.field]
+		hit := FuzzyHit{
+			ID:         tx.docs[p.doc].ID,
+			Field:      field,
+			Value:      tx.docs[p.doc].Fields[field][p.value],
+			Similarity: similarity,
+		}
+		if b, ok := best[p.doc]; !ok || betterFuzzyHit(hit, b) {
+			best[p.doc] = hit
+		}
+	}
+
+	hits := make([]FuzzyHit, 0, len(best))
+	for _, hit := range best {
+		hits = append(hits, hit)
+	}
+	sort.Slice(hits, func(i, j int) bool {
+		return betterFuzzyHit(hits[i], hits[j])
+	})
+	if len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits
+}
+
+func betterFuzzyHit(a, b FuzzyHit) bool {
+	if a.Similarity != b.Similarity {
+		return a.Similarity > b.Similarity
+	}
+	if len(a.Value) != len(b.Value) {
+		return len(a.Value) < len(b.Value)
+	}
+	if a.Value != b.Value {
+		return a.Value < b.Value
+	}
+	return a.ID < b.ID
+}
+
+// trigrams returns the set of character trigrams of each term in s, padded
+// so word boundaries count, e.g. "cat" gives "  c", " ca", "cat" and "at ".
+func trigrams(s string) map[string]bool {
+	grams := map[string]bool{}
+	for _, term := range Tokenize(s) {
+		padded := []rune("  " + term + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			grams[string(padded[i:i+3])] = true
+		}
+	}
+	return grams
+}
+
+// Suggest corrects each query word that is not in the index vocabulary to the
+// closest indexed term within a small edit distance, preferring terms found
+// in more documents. The correction is spelled as the word the term was most
+// often indexed from, e.g. "analysis" rather than the stem "analysi". It
+// returns "" when every word is already known or some word has no close
+// match.
+func (ix *Index) Suggest(query string) string {
+	words := strings.Fields(query)
+	corrected := false
+
+	for i, word := range words {
+		terms := Tokenize(word)
+		if len(terms) != 1 || len(ix.postings[terms[0]]) > 0 {
+			continue
+		}
+
+		replacement := ix.closestTerm(terms[0])
+		if replacement == "" {
+			return ""
+		}
+		words[i] = ix.words[replacement]
+		corrected = true
+	}
+
+	if !corrected {
+		return ""
+	}
+	return strings.Join(words, " ")
+}
+
+func (ix *Index) closestTerm(term string) string {
+	maxDistance := 2
+	if len(term) <= 4 {
+		maxDistance = 1
+	}
+
+	best, bestDistance, bestDF := "", maxDistance+1, 0
+	for candidate, postings := range ix.postings {
+		if abs(len(candidate)-len(term)) > maxDistance {
+			continue
+		}
+		d := levenshtein(term, candidate)
+		if d < bestDistance || (d == bestDistance && (len(postings) > bestDF || (len(postings) == bestDF && candidate < best))) {
+			best, bestDistance, bestDF = candidate, d, len(postings)
+		}
+	}
+
+	return best
+}
+
+// levenshtein returns the edit distance between a and b.
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(br)]
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
@@ -0,0 +1,63 @@
+package search
+
+import "testing"
+
+func TestLevenshtein(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"", "", 0},
+		{"abc", "", 3},
+		{"kitten", "sitting", 3},
+		{"acountant", "accountant", 1},
+		{"manager", "manager", 0},
+	}
+	for _, tt := range tests {
+		if got := levenshtein(tt.a, tt.b); got != tt.want {
+			t.Errorf("levenshtein(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestTrigramIndexSearch(t *testing.T) {
+	tx := NewTrigramIndex([]Document{
+		doc("acct", map[string][]string{"title": {"Accountants and Auditors"}}),
+		doc("eng", map[string][]string{"title": {"Architectural and Engineering Managers"}, "lay": {"Engineering Manager"}}),
+		doc("cook", map[string][]string{"title": {"Cooks"}}),
+	}, []string{"title", "lay"})
+
+	tests := []struct {
+		query     string
+		wantFirst string
+		wantValue string
+	}{
+		{"acountant", "acct", "Accountants and Auditors"},
+		{"enginer manager", "eng", "Engineering Manager"},
+	}
+	for _, tt := range tests {
+		hits := tx.Search(tt.query, 10)
+		if len(hits) == 0 {
+			t.Errorf("Search(%q) found nothing", tt.query)
+			continue
+		}
+		if hits[0].ID != tt.wantFirst || hits[0].Value != tt.wantValue {
+			t.Errorf("Search(%q) first = %s %q, want %s %q", tt.query, hits[0].ID, hits[0].Value, tt.wantFirst, tt.wantValue)
+		}
+		for _, h := range hits {
+			if h.ID == "cook" {
+				t.Errorf("Search(%q) matched unrelated %q with similarity %v", tt.query, h.Value, h.Similarity)
+			}
+			if h.Similarity < minSimilarity || h.Similarity > 1 {
+				t.Errorf("Search(%q) similarity %v out of range", tt.query, h.Similarity)
+			}
+		}
+	}
+
+	if hits := tx.Search("acountant", 0); len(hits) != 0 {
+		t.Errorf("Search with limit 0 returned %d hits", len(hits))
+	}
+	if hits := tx.Search("", 10); len(hits) != 0 {
+		t.Errorf("Search(\"\") returned %d hits", len(hits))
+	}
+}
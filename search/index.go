@@ -0,0 +1,250 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Document is a unit of indexing. Each field may hold several values, e.g.
+// all of an occupation's lay titles.
+type Document struct {
+	ID     string
+	Fields map[string][]string
+}
+
+// Hit is a document matching a query with its relevance score. Matches holds
+// the best matching value of each matched field, highest weighted score first.
+type Hit struct {
+	ID      string
+	Score   float64
+	Matches []Match
+}
+
+// Match is the value of a field that best matched a query.
+type Match struct {
+	Field string
+	Value string
+	Score float64
+}
+
+type posting struct {
+	doc   int
+	field string
+	value int
+	tf    int
+}
+
+// Index is an immutable in-memory inverted index with per-field weights.
+// Build a new Index to pick up document changes.
+type Index struct {
+	weights  map[string]float64
+	docs     []Document
+	postings map[string][]posting
+	// words maps each term to the word it most often came from, so
+	// suggestions read as words rather than stems
+	words map[string]string
+}
+
+// NewIndex indexes docs. Fields without a weight are ignored.
+func NewIndex(docs []Document, weights map[string]float64) *Index {
+	ix := &Index{
+		weights:  weights,
+		docs:     docs,
+		postings: map[string][]posting{},
+		words:    map[string]string{},
+	}
+
+	wordCounts := map[string]map[string]int{}
+	for i, doc := range docs {
+		for field, values := range doc.Fields {
+			if weights[field] == 0 {
+				continue
+			}
+			for vi, v := range values {
+				tf := map[string]int{}
+				for _, t := range tokenize(v) {
+					tf[t.term]++
+					if wordCounts[t.term] == nil {
+						wordCounts[t.term] = map[string]int{}
+					}
+					wordCounts[t.term][t.word]++
+				}
+				for term, n := range tf {
+					ix.postings[term] = append(ix.postings[term], posting{doc: i, field: field, value: vi, tf: n})
+				}
+			}
+		}
+	}
+
+	for term, counts := range wordCounts {
+		best := ""
+		for word, n := range counts {
+			if best == "" || n > counts[best] || (n == counts[best] && word < best) {
+				best = word
+			}
+		}
+		ix.words[term] = best
+	}
+
+	return ix
+}
+
+// Search returns every document matching at least one query term, best
+// first. Each term contributes its inverse document frequency, with repeated
+// occurrences saturating BM25-style. Values of a multi-valued field are
+// scored separately and only the best one counts, times the field weight.
+// Documents matching only some of the terms are penalised by the square of
+// the fraction of terms they matched.
+func (ix *Index) Search(query string) []Hit {
+	terms := uniqueTerms(Tokenize(query))
+	if len(terms) == 0 {
+		return []Hit{}
+	}
+
+	type valueKey struct {
+		doc   int
+		field string
+		value int
+	}
+	valueScores := map[valueKey]float64{}
+	matched := map[int]map[int]bool{}
+	n := float64(len(ix.docs))
+
+	for ti, term := range terms {
+		postings := ix.postings[term]
+		if len(postings) == 0 {
+			continue
+		}
+
+		docs := map[int]bool{}
+		for _, p := range postings {
+			docs[p.doc] = true
+		}
+		df := float64(len(docs))
+		idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+
+		for _, p := range postings {
+			tf := float64(p.tf)
+			valueScores[valueKey{p.doc, p.field, p.value}] += idf * (tf * 2.2) / (tf + 1.2)
+			if matched[p.doc] == nil {
+				matched[p.doc] = map[int]bool{}
+			}
+			matched[p.doc][ti] = true
+		}
+	}
+
+	// Keep the best value of each field
+	type fieldKey struct {
+		doc   int
+		field string
+	}
+	best := map[fieldKey]valueKey{}
+	for k, score := range valueScores {
+		fk := fieldKey{k.doc, k.field}
+		if b, ok := best[fk]; !ok || score > valueScores[b] || (score == valueScores[b] && k.value < b.value) {
+			best[fk] = k
+		}
+	}
+
+	byDoc := map[int]*Hit{}
+	for fk, k := range best {
+		hit, ok := byDoc[fk.doc]
+		if !ok {
+			hit = &Hit{ID: ix.docs[fk.doc].ID}
+			byDoc[fk.doc] = hit
+		}
+		score := ix.weights[fk.field] * valueScores[k]
+		hit.Score += score
+		hit.Matches = append(hit.Matches, Match{
+			Field: fk.field,
+			Value: ix.docs[fk.doc].Fields[fk.field][k.value],
+			Score: score,
+		})
+	}
+
+	hits := make([]Hit, 0, len(byDoc))
+	for doc, hit := range byDoc {
+		coverage := float64(len(matched[doc])) / float64(len(terms))
+		hit.Score = math.Round(hit.Score*coverage*coverage*1000) / 1000
+		sort.Slice(hit.Matches, func(i, j int) bool {
+			if hit.Matches[i].Score != hit.Matches[j].Score {
+				return hit.Matches[i].Score > hit.Matches[j].Score
+			}
+			return hit.Matches[i].Field < hit.Matches[j].Field
+		})
+		hits = append(hits, *hit)
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	return hits
+}
+
+var stopWords = map[string]bool{
+	"a": true, "an": true, "and": true, "are": true, "as": true, "at": true,
+	"be": true, "by": true, "for": true, "from": true, "in": true, "is": true,
+	"of": true, "on": true, "or": true, "the": true, "to": true, "with": true,
+}
+
+// Tokenize lower-cases s, splits it on anything that is not a letter or
+// digit, drops stop words and reduces plurals so "Managers" matches "manager".
+func Tokenize(s string) []string {
+	tokens := tokenize(s)
+	terms := make([]string, len(tokens))
+	for i, t := range tokens {
+		terms[i] = t.term
+	}
+	return terms
+}
+
+// token is a lower-cased word and the term it reduces to.
+type token struct {
+	word string
+	term string
+}
+
+func tokenize(s string) []token {
+	words := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+
+	tokens := make([]token, 0, len(words))
+	for _, w := range words {
+		if stopWords[w] {
+			continue
+		}
+		tokens = append(tokens, token{word: w, term: stem(w)})
+	}
+	return tokens
+}
+
+// stem strips common English plural endings. It is deliberately crude; it
+// only has to map a word and its plural to the same term.
+func stem(w string) string {
+	switch {
+	case len(w) > 4 && strings.HasSuffix(w, "ies"):
+		return w[:len(w)-3] + "y"
+	case len(w) > 3 && strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss"):
+		return w[:len(w)-1]
+	}
+	return w
+}
+
+func uniqueTerms(terms []string) []string {
+	seen := map[string]bool{}
+	unique := terms[:0:0]
+	for _, t := range terms {
+		if !seen[t] {
+			seen[t] = true
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
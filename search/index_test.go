@@ -0,0 +1,152 @@
+package search
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []string
+	}{
+		{"Managers", []string{"manager"}},
+		{"Chief Executives", []string{"chief", "executive"}},
+		{"Accountants and Auditors", []string{"accountant", "auditor"}},
+		{"Buyers, Farm Products", []string{"buyer", "farm", "product"}},
+		{"Secretaries of the Board", []string{"secretary", "board"}},
+		{"K-12 Teachers", []string{"k", "12", "teacher"}},
+		{"the of and", []string{}},
+		{"", []string{}},
+	}
+	for _, tt := range tests {
+		if got := Tokenize(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Tokenize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		in, want string
+	}{
+		{"managers", "manager"},
+		{"secretaries", "secretary"},
+		{"analysis", "analysi"},
+		{"business", "business"},
+		{"gas", "gas"},
+		{"ties", "tie"},
+		{"analyst", "analyst"},
+	}
+	for _, tt := range tests {
+		if got := stem(tt.in); got != tt.want {
+			t.Errorf("stem(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func doc(id string, fields map[string][]string) Document {
+	return Document{ID: id, Fields: fields}
+}
+
+var testWeights = map[string]float64{"title": 3, "description": 1}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, h := range hits {
+		ids[i] = h.ID
+	}
+	return ids
+}
+
+func TestIndexSearchOrdering(t *testing.T) {
+	ix := NewIndex([]Document{
+		doc("desc", map[string][]string{"title": {"Clerks"}, "description": {"Work with a manager"}}),
+		doc("title", map[string][]string{"title": {"Managers"}, "description": {"Direct staff"}}),
+		doc("none", map[string][]string{"title": {"Cooks"}, "description": {"Prepare food"}}),
+		doc("ignored", map[string][]string{"notes": {"manager"}}),
+	}, testWeights)
+
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{"manager", []string{"title", "desc"}},
+		{"Managers", []string{"title", "desc"}},
+		{"food", []string{"none"}},
+		{"astronaut", []string{}},
+		{"the", []string{}},
+	}
+	for _, tt := range tests {
+		if got := hitIDs(ix.Search(tt.query)); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Search(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
+
+func TestIndexSearchMatches(t *testing.T) {
+	ix := NewIndex([]Document{
+		doc("a", map[string][]string{"title": {"Cooks", "Line Chefs"}, "description": {"Chefs cook"}}),
+		doc("b", map[string][]string{"title": {"Bakers"}}),
+	}, testWeights)
+
+	hits := ix.Search("chef")
+	if len(hits) != 1 {
+		t.Fatalf("Search(chef) returned %d hits, want 1", len(hits))
+	}
+	matches := hits[0].Matches
+	if len(matches) != 2 || matches[0].Field != "title" || matches[0].Value != "Line Chefs" || matches[1].Field != "description" {
+		t.Errorf("Search(chef) matches = %+v, want the title value then the description", matches)
+	}
+}
+
+func TestIndexSearchCoveragePenalty(t *testing.T) {
+	docs := []Document{
+		doc("both", map[string][]string{"description": {"financial analyst"}}),
+		doc("one", map[string][]string{"title": {"Financial Managers"}}),
+		doc("other1", map[string][]string{"description": {"cook"}}),
+		doc("other2", map[string][]string{"description": {"baker"}}),
+	}
+	ix := NewIndex(docs, testWeights)
+
+	hits := ix.Search("financial analyst")
+	if got := hitIDs(hits); !reflect.DeepEqual(got, []string{"both", "one"}) {
+		t.Fatalf("Search = %q, want full coverage first", got)
+	}
+
+	// Matching one of two terms keeps a quarter of the score
+	single := ix.Search("financial")
+	var full float64
+	for _, h := range single {
+		if h.ID == "one" {
+			full = h.Score
+		}
+	}
+	if want := math.Round(full/4*1000) / 1000; math.Abs(hits[1].Score-want) > 0.002 {
+		t.Errorf("half-coverage score = %v, want about %v (a quarter of %v)", hits[1].Score, want, full)
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	ix := NewIndex([]Document{
+		doc("1", map[string][]string{"title": {"Financial Analysis Managers"}, "description": {"Logistics and analysis"}}),
+		doc("2", map[string][]string{"title": {"Logistics Analysts"}}),
+	}, testWeights)
+
+	tests := []struct {
+		query, want string
+	}{
+		// Corrections are spelled as indexed words, not stems
+		{"anlysis", "analysis"},
+		{"logistcs", "logistics"},
+		{"finacial anlysis", "financial analysis"},
+		{"financial analysis", ""},
+		{"zzzzzzzz", ""},
+	}
+	for _, tt := range tests {
+		if got := ix.Suggest(tt.query); got != tt.want {
+			t.Errorf("Suggest(%q) = %q, want %q", tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package search
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// Suggestion is a field value whose text starts with, or has a word starting
+// with, a looked-up prefix.
+type Suggestion struct {
+	ID    string
+	Field string
+	Value string
+}
+
+type prefixEntry struct {
+	key   string
+	doc   int
+	field int
+	value int
+	inner bool
+}
+
+// PrefixIndex is an immutable sorted index of lower-cased field values for
+// typeahead lookups. Every word of a value is indexed, so "exec" finds
+// "Chief Executives".
+type PrefixIndex struct {
+	docs    []Document
+	fields  []string
+	entries []prefixEntry
+}
+
+// NewPrefixIndex indexes the given fields of docs. Fields are listed in
+// priority order: on otherwise equal matches, earlier fields rank first.
+func NewPrefixIndex(docs []Document, fields []string) *PrefixIndex {
+	px := &PrefixIndex{docs: docs, fields: fields}
+
+	for di, doc := range docs {
+		for fi, field := range fields {
+			for vi, v := range doc.Fields[field] {
+				for _, start := range wordStarts(v) {
+					px.entries = append(px.entries, prefixEntry{
+						key:   normalize(v[start:]),
+						doc:   di,
+						field: fi,
+						value: vi,
+						inner: start > 0,
+					})
+				}
+			}
+		}
+	}
+
+	sort.Slice(px.entries, func(i, j int) bool {
+		return px.entries[i].key < px.entries[j].key
+	})
+
+	return px
+}
+
+// Lookup returns up to limit values matching prefix. Values that start with
+// the prefix rank before values where only a later word does, then by field
+// priority, then shorter values first. Identical values of one document are
+// reported once.
+func (px *PrefixIndex) Lookup(prefix string, limit int) []Suggestion {
+	prefix = normalize(prefix)
+	if prefix == "" {
+		return []Suggestion{}
+	}
+
+	start := sort.Search(len(px.entries), func(i int) bool {
+		return px.entries[i].key >= prefix
+	})
+
+	var matches []prefixEntry
+	for i := start; i < len(px.entries) && strings.HasPrefix(px.entries[i].key, prefix); i++ {
+		matches = append(matches, px.entries[i])
+	}
+
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.inner != b.inner {
+			return !a.inner
+		}
+		if a.field != b.field {
+			return a.field < b.field
+		}
+		av, bv := px.value(a), px.value(b)
+		if len(av) != len(bv) {
+			return len(av) < len(bv)
+		}
+		if av != bv {
+			return av < bv
+		}
+		return px.docs[a.doc].ID < px.docs[b.doc].ID
+	})
+
+	type seenKey struct {
+		doc   int
+		value string
+	}
+	seen := map[seenKey]bool{}
+	suggestions := []Suggestion{}
+	for _, m := range matches {
+		if len(suggestions) == limit {
+			break
+		}
+		value := px.value(m)
+		key := seenKey{m.doc, strings.ToLower(value)}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		suggestions = append(suggestions, Suggestion{
+			ID:    px.docs[m.doc].ID,
+			Field: px.fields[m.field],
+			Value: value,
+		})
+	}
+
+	return suggestions
+}
+
+func (px *PrefixIndex) value(e prefixEntry) string {
+	return px.docs[e.doc].Fields[px.fields[e.field]][e.value]
+}
+
+// wordStarts returns the byte offsets in s where a run of letters or digits
+// begins.
+func wordStarts(s string) []int {
+	var starts []int
+	inWord := false
+	for i, r := range s {
+		isWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		if isWord && !inWord {
+			starts = append(starts, i)
+		}
+		inWord = isWord
+	}
+	return starts
+}
+
+// normalize lower-cases s and collapses runs of whitespace to single spaces.
+func normalize(s string) string {
+	return strings.Join(strings.Fields(strings.ToLower(s)), " ")
+}
@@ -0,0 +1,55 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPrefixIndexLookup(t *testing.T) {
+	px := NewPrefixIndex([]Document{
+		doc("ceo", map[string][]string{"title": {"Chief Executives"}, "short": {"Chief Executive", "chief executives"}}),
+		doc("chef", map[string][]string{"title": {"Chefs and Head Cooks"}}),
+		doc("exec", map[string][]string{"title": {"Executive Secretaries"}}),
+	}, []string{"title", "short"})
+
+	type result struct{ id, value string }
+	tests := []struct {
+		prefix string
+		limit  int
+		want   []result
+	}{
+		// Values starting with the prefix come first, then by field
+		// priority and length; a later word also matches
+		{"chief", 10, []result{{"ceo", "Chief Executives"}, {"ceo", "Chief Executive"}}},
+		{"exec", 10, []result{{"exec", "Executive Secretaries"}, {"ceo", "Chief Executives"}, {"ceo", "Chief Executive"}}},
+		{"ch", 2, []result{{"ceo", "Chief Executives"}, {"chef", "Chefs and Head Cooks"}}},
+		{"  HEAD   co", 10, []result{{"chef", "Chefs and Head Cooks"}}},
+		{"zz", 10, []result{}},
+		{"", 10, []result{}},
+	}
+	for _, tt := range tests {
+		got := []result{}
+		for _, s := range px.Lookup(tt.prefix, tt.limit) {
+			got = append(got, result{s.ID, s.Value})
+		}
+		if !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("Lookup(%q, %d) = %v, want %v", tt.prefix, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestWordStarts(t *testing.T) {
+	tests := []struct {
+		in   string
+		want []int
+	}{
+		{"Chief Executives", []int{0, 6}},
+		{"  K-12 teachers", []int{2, 4, 7}},
+		{"", nil},
+	}
+	for _, tt := range tests {
+		if got := wordStarts(tt.in); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("wordStarts(%q) = %v, want %v", tt.in, got, tt.want)
+		}
+	}
+}
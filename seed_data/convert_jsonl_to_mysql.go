@@ -7,42 +7,9 @@ import (
 	"fmt"
 	"os"
 	"strings"
-)
-
-type Occupation struct {
-	ID              string      `json:"id"`
-	SocID           string      `json:"socId"`
-	SocTitle        string      `json:"socTitle"`
-	Title           string      `json:"title"`
-	SingularTitle   string      `json:"singularTitle"`
-	Description     string      `json:"description"`
-	TypicalEdLevel  string      `json:"typicalEdLevel"`
-	CoreTasks       []string    `json:"coreTasks"`
-	Skills          []Skill     `json:"skills"`
-	Knowledge       []Knowledge `json:"knowledge"`
-	Abilities       []Ability   `json:"abilities"`
-}
-
-type Skill struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Importance  float64 `json:"importance"`
-	Level       float64 `json:"level"`
-}
-
-type Knowledge struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Importance  float64 `json:"importance"`
-	Level       float64 `json:"level"`
-}
 
-type Ability struct {
-	Name        string  `json:"name"`
-	Description string  `json:"description"`
-	Importance  float64 `json:"importance"`
-	Level       float64 `json:"level"`
-}
+	"go-careers/models"
+)
 
 func escapeString(s string) string {
 	s = strings.ReplaceAll(s, "\\", "\\\\")
@@ -60,9 +27,11 @@ CREATE TABLE IF NOT EXISTS occupations (
     singular_title VARCHAR(255),
     description TEXT,
     typical_ed_level VARCHAR(100),
+    title_slug VARCHAR(255),
     data JSON,
     INDEX idx_soc_id (soc_id),
-    INDEX idx_title (title)
+    INDEX idx_title (title),
+    INDEX idx_title_slug (title_slug)
 );
 
 CREATE TABLE IF NOT EXISTS occupation_tasks (
@@ -70,7 +39,50 @@ CREATE TABLE IF NOT EXISTS occupation_tasks (
     occupation_id VARCHAR(20),
     task TEXT,
     FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
-    INDEX idx_occupation_id (occupation_id)
+    INDEX idx_occupation_id (occupation_id),
+    FULLTEXT INDEX ft_task (task)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_alt_titles (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    title VARCHAR(255),
+    source VARCHAR(10),
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    INDEX idx_title (title)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_military (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    moc_code VARCHAR(20),
+    branch VARCHAR(30),
+    category VARCHAR(20),
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    INDEX idx_moc_code (moc_code)
+);
+
+CREATE TABLE IF NOT EXISTS career_clusters (
+    id INT PRIMARY KEY,
+    name VARCHAR(255)
+);
+
+CREATE TABLE IF NOT EXISTS career_pathways (
+    id VARCHAR(10) PRIMARY KEY,
+    cluster_id INT,
+    name VARCHAR(255),
+    INDEX idx_cluster_id (cluster_id)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_pathways (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    pathway_id VARCHAR(10),
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    INDEX idx_pathway_id (pathway_id)
 );
 
 CREATE TABLE IF NOT EXISTS occupation_skills (
@@ -113,12 +125,12 @@ CREATE TABLE IF NOT EXISTS occupation_abilities (
 	f.WriteString(schema)
 }
 
-func generateSQL(occ Occupation, rawJSON []byte) string {
+func generateSQL(occ models.OccupationRecord, rawJSON []byte) string {
 	var sql strings.Builder
 
 	// Main occupation insert
 	sql.WriteString(fmt.Sprintf(
-		"INSERT INTO occupations (id, soc_id, soc_title, title, singular_title, description, typical_ed_level, data)\nVALUES (%s, %s, %s, %s, %s, %s, %s, %s);\n\n",
+		"INSERT INTO occupations (id, soc_id, soc_title, title, singular_title, description, typical_ed_level, title_slug, data)\nVALUES (%s, %s, %s, %s, %s, %s, %s, %s, %s);\n\n",
 		escapeString(occ.ID),
 		escapeString(occ.SocID),
 		escapeString(occ.SocTitle),
@@ -126,6 +138,7 @@ func generateSQL(occ Occupation, rawJSON []byte) string {
 		escapeString(occ.SingularTitle),
 		escapeString(occ.Description),
 		escapeString(occ.TypicalEdLevel),
+		escapeString(occ.Slug()),
 		escapeString(string(rawJSON)),
 	))
 
@@ -141,6 +154,63 @@ func generateSQL(occ Occupation, rawJSON []byte) string {
 		sql.WriteString("\n")
 	}
 
+	// Insert alternate titles (lay titles and EMSI job posting titles)
+	for _, title := range occ.LayTitles {
+		sql.WriteString(fmt.Sprintf(
+			"INSERT INTO occupation_alt_titles (occupation_id, title, source) VALUES (%s, %s, 'lay');\n",
+			escapeString(occ.ID),
+			escapeString(title),
+		))
+	}
+	for _, title := range occ.EmsiTitles {
+		sql.WriteString(fmt.Sprintf(
+			"INSERT INTO occupation_alt_titles (occupation_id, title, source) VALUES (%s, %s, 'emsi');\n",
+			escapeString(occ.ID),
+			escapeString(title),
+		))
+	}
+	if len(occ.LayTitles)+len(occ.EmsiTitles) > 0 {
+		sql.WriteString("\n")
+	}
+
+	// Insert military crosswalk codes
+	for _, code := range occ.Mocs {
+		moc, err := models.ParseMOC(code)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: skipping %v for %s\n", err, occ.ID)
+			continue
+		}
+		sql.WriteString(fmt.Sprintf(
+			"INSERT INTO occupation_military (occupation_id, moc_code, branch, category) VALUES (%s, %s, %s, %s);\n",
+			escapeString(occ.ID),
+			escapeString(moc.Code),
+			escapeString(moc.Branch),
+			escapeString(moc.Category),
+		))
+	}
+	if len(occ.Mocs) > 0 {
+		sql.WriteString("\n")
+	}
+
+	// Insert career pathways. Pathways missing from the lookup file are added
+	// without a name so they can still be browsed.
+	for _, pathway := range occ.Pathways {
+		clusterID, _, _ := strings.Cut(pathway, ".")
+		sql.WriteString(fmt.Sprintf(
+			"INSERT IGNORE INTO career_pathways (id, cluster_id) VALUES (%s, %s);\n",
+			escapeString(pathway),
+			escapeString(clusterID),
+		))
+		sql.WriteString(fmt.Sprintf(
+			"INSERT INTO occupation_pathways (occupation_id, pathway_id) VALUES (%s, %s);\n",
+			escapeString(occ.ID),
+			escapeString(pathway),
+		))
+	}
+	if len(occ.Pathways) > 0 {
+		sql.WriteString("\n")
+	}
+
 	// Insert skills
 	for _, skill := range occ.Skills {
 		sql.WriteString(fmt.Sprintf(
@@ -189,7 +259,163 @@ func generateSQL(occ Occupation, rawJSON []byte) string {
 	return sql.String()
 }
 
-func convertJSONLToSQL(inputFile, outputFile string, maxLines int) error {
+// writeClusters writes the career cluster and pathway name lookup tables.
+// With upsert, names already in the tables are replaced instead of failing
+// the insert.
+func writeClusters(f *os.File, clustersFile string, upsert bool) error {
+	data, err := os.ReadFile(clustersFile)
+	if err != nil {
+		return fmt.Errorf("error reading clusters file: %w", err)
+	}
+
+	var clusters []models.ClusterNames
+	if err := json.Unmarshal(data, &clusters); err != nil {
+		return fmt.Errorf("error parsing clusters file: %w", err)
+	}
+
+	onDuplicate := ""
+	if upsert {
+		onDuplicate = " AS new ON DUPLICATE KEY UPDATE name = new.name"
+	}
+
+	f.WriteString("-- Career cluster and pathway names\n\n")
+	for _, cluster := range clusters {
+		f.WriteString(fmt.Sprintf(
+			"INSERT INTO career_clusters (id, name) VALUES (%d, %s)%s;\n",
+			cluster.ID,
+			escapeString(cluster.Name),
+			onDuplicate,
+		))
+		for _, pathway := range cluster.Pathways {
+			name := "NULL"
+			if pathway.Name != "" {
+				name = escapeString(pathway.Name)
+			}
+			f.WriteString(fmt.Sprintf(
+				"INSERT INTO career_pathways (id, cluster_id, name) VALUES (%s, %d, %s)%s;\n",
+				escapeString(pathway.ID),
+				cluster.ID,
+				name,
+				onDuplicate,
+			))
+		}
+	}
+	f.WriteString("\n")
+
+	return nil
+}
+
+// addIfMissing runs alter unless information_schema already lists the column
+// or index, since MySQL 8.0 has no ADD COLUMN IF NOT EXISTS.
+func addIfMissing(f *os.File, schemaTable, table, column, alter string) {
+	f.WriteString(fmt.Sprintf(
+		"SET @ddl = (SELECT IF(COUNT(*) = 0, %s, 'DO 0') FROM information_schema.%s\n"+
+			"    WHERE table_schema = DATABASE() AND table_name = '%s' AND %s);\n"+
+			"PREPARE stmt FROM @ddl;\nEXECUTE stmt;\nDEALLOCATE PREPARE stmt;\n\n",
+		escapeString(alter), schemaTable, table, column,
+	))
+}
+
+// mocCases returns a CASE expression decoding one letter of moc_code, built
+// from models.ParseMOC so the migration labels codes as the API does.
+func mocCases(position int, field func(models.MilitaryCode) string) string {
+	var cases strings.Builder
+	cases.WriteString(fmt.Sprintf("CASE SUBSTRING(jt.code, %d, 1)", position))
+	for c := 'A'; c <= 'Z'; c++ {
+		code := string(c) + "_" + string(c) + "_1"
+		if moc, err := models.ParseMOC(code); err == nil && field(moc) != models.MilitaryUnknown {
+			cases.WriteString(fmt.Sprintf(" WHEN '%c' THEN %s", c, escapeString(field(moc))))
+		}
+	}
+	cases.WriteString(" ELSE " + escapeString(models.MilitaryUnknown) + " END")
+	return cases.String()
+}
+
+// writeMigration writes a script that brings a database seeded by an older
+// version of this converter up to the current schema, filling the new
+// columns and tables from each occupation's data JSON. It only adds what is
+// missing, so it can be run more than once.
+func writeMigration(outputFile, clustersFile string) error {
+	f, err := os.Create(outputFile)
+	if err != nil {
+		return fmt.Errorf("error creating migration file: %w", err)
+	}
+	defer f.Close()
+
+	f.WriteString("-- Upgrades a database created from an older seed_data.sql; see the README\n\n")
+	writeSchema(f)
+
+	f.WriteString("-- Columns and indexes added to existing tables\n\n")
+	addIfMissing(f, "columns", "occupations", "column_name = 'title_slug'",
+		"ALTER TABLE occupations ADD COLUMN title_slug VARCHAR(255) AFTER typical_ed_level, ADD INDEX idx_title_slug (title_slug)")
+	addIfMissing(f, "statistics", "occupation_tasks", "index_name = 'ft_task'",
+		"ALTER TABLE occupation_tasks ADD FULLTEXT INDEX ft_task (task)")
+
+	f.WriteString(`-- Backfill from the stored records. Child rows are only added for
+-- occupations that have none yet.
+
+UPDATE occupations
+SET title_slug = COALESCE(
+    NULLIF(JSON_UNQUOTE(JSON_EXTRACT(data, '$.titleSlug')), ''),
+    TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(COALESCE(
+        NULLIF(JSON_UNQUOTE(JSON_EXTRACT(data, '$.shortTitle')), ''),
+        NULLIF(singular_title, ''),
+        title
+    )), '[^a-z0-9]+', '-'))
+)
+WHERE title_slug IS NULL;
+
+INSERT INTO occupation_alt_titles (occupation_id, title, source)
+SELECT o.id, jt.title, jt.source
+FROM occupations o,
+    JSON_TABLE(JSON_ARRAY(
+        JSON_OBJECT('source', 'lay', 'titles', COALESCE(JSON_EXTRACT(o.data, '$.layTitles'), JSON_ARRAY())),
+        JSON_OBJECT('source', 'emsi', 'titles', COALESCE(JSON_EXTRACT(o.data, '$.emsiTitles'), JSON_ARRAY()))
+    ), '$[*]' COLUMNS (
+        source VARCHAR(10) PATH '$.source',
+        NESTED PATH '$.titles[*]' COLUMNS (title VARCHAR(255) PATH '$')
+    )) jt
+WHERE jt.title IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM occupation_alt_titles a WHERE a.occupation_id = o.id)
+ORDER BY o.id;
+
+`)
+	f.WriteString(fmt.Sprintf(`INSERT INTO occupation_military (occupation_id, moc_code, branch, category)
+SELECT o.id, jt.code,
+    %s,
+    %s
+FROM occupations o,
+    JSON_TABLE(COALESCE(JSON_EXTRACT(o.data, '$.mocs'), JSON_ARRAY()), '$[*]' COLUMNS (code VARCHAR(20) PATH '$')) jt
+WHERE NOT EXISTS (SELECT 1 FROM occupation_military m WHERE m.occupation_id = o.id)
+ORDER BY o.id;
+
+`,
+		mocCases(1, func(m models.MilitaryCode) string { return m.Branch }),
+		mocCases(3, func(m models.MilitaryCode) string { return m.Category }),
+	))
+	f.WriteString(`INSERT IGNORE INTO career_pathways (id, cluster_id)
+SELECT DISTINCT jt.pathway, SUBSTRING_INDEX(jt.pathway, '.', 1)
+FROM occupations o,
+    JSON_TABLE(COALESCE(JSON_EXTRACT(o.data, '$.pathways'), JSON_ARRAY()), '$[*]' COLUMNS (pathway VARCHAR(10) PATH '$')) jt;
+
+INSERT INTO occupation_pathways (occupation_id, pathway_id)
+SELECT o.id, jt.pathway
+FROM occupations o,
+    JSON_TABLE(COALESCE(JSON_EXTRACT(o.data, '$.pathways'), JSON_ARRAY()), '$[*]' COLUMNS (pathway VARCHAR(10) PATH '$')) jt
+WHERE NOT EXISTS (SELECT 1 FROM occupation_pathways p WHERE p.occupation_id = o.id)
+ORDER BY o.id;
+
+`)
+
+	if err := writeClusters(f, clustersFile, true); err != nil {
+		return err
+	}
+
+	fmt.Printf("Migration written to: %s\n", outputFile)
+	return nil
+}
+
+func convertJSONLToSQL(inputFile, outputFile, clustersFile string, maxLines int) error {
 	input, err := os.Open(inputFile)
 	if err != nil {
 		return fmt.Errorf("error opening input file: %w", err)
@@ -204,6 +430,9 @@ func convertJSONLToSQL(inputFile, outputFile string, maxLines int) error {
 
 	// Write schema
 	writeSchema(output)
+	if err := writeClusters(output, clustersFile, false); err != nil {
+		return err
+	}
 	output.WriteString("-- Data inserts\n\n")
 
 	scanner := bufio.NewScanner(input)
@@ -219,7 +448,7 @@ func convertJSONLToSQL(inputFile, outputFile string, maxLines int) error {
 			continue
 		}
 
-		var occ Occupation
+		var occ models.OccupationRecord
 		if err := json.Unmarshal(line, &occ); err != nil {
 			fmt.Fprintf(os.Stderr, "Warning: skipping invalid JSON on line %d: %v\n", lineCount+1, err)
 			continue
@@ -248,12 +477,20 @@ func convertJSONLToSQL(inputFile, outputFile string, maxLines int) error {
 func main() {
 	inputFile := flag.String("input", "occupations.jsonl", "Input JSONL file")
 	outputFile := flag.String("output", "seed_data.sql", "Output SQL file")
+	clustersFile := flag.String("clusters", "career_clusters.json", "Career cluster and pathway names JSON file")
 	maxLines := flag.Int("lines", 100, "Maximum number of lines to process (0 for all)")
+	migrationFile := flag.String("migration", "migrate.sql", "Output SQL file upgrading an existing database (empty to skip)")
 
 	flag.Parse()
 
-	if err := convertJSONLToSQL(*inputFile, *outputFile, *maxLines); err != nil {
+	if err := convertJSONLToSQL(*inputFile, *outputFile, *clustersFile, *maxLines); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		os.Exit(1)
 	}
+	if *migrationFile != "" {
+		if err := writeMigration(*migrationFile, *clustersFile); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+	}
 }
\ No newline at end of file
@@ -0,0 +1,241 @@
+-- Upgrades a database created from an older seed_data.sql; see the README
+
+-- Database schema for occupations data
+CREATE TABLE IF NOT EXISTS occupations (
+    id VARCHAR(20) PRIMARY KEY,
+    soc_id VARCHAR(20),
+    soc_title VARCHAR(255),
+    title VARCHAR(255),
+    singular_title VARCHAR(255),
+    description TEXT,
+    typical_ed_level VARCHAR(100),
+    title_slug VARCHAR(255),
+    data JSON,
+    INDEX idx_soc_id (soc_id),
+    INDEX idx_title (title),
+    INDEX idx_title_slug (title_slug)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_tasks (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    task TEXT,
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    FULLTEXT INDEX ft_task (task)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_alt_titles (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    title VARCHAR(255),
+    source VARCHAR(10),
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    INDEX idx_title (title)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_military (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    moc_code VARCHAR(20),
+    branch VARCHAR(30),
+    category VARCHAR(20),
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    INDEX idx_moc_code (moc_code)
+);
+
+CREATE TABLE IF NOT EXISTS career_clusters (
+    id INT PRIMARY KEY,
+    name VARCHAR(255)
+);
+
+CREATE TABLE IF NOT EXISTS career_pathways (
+    id VARCHAR(10) PRIMARY KEY,
+    cluster_id INT,
+    name VARCHAR(255),
+    INDEX idx_cluster_id (cluster_id)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_pathways (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    pathway_id VARCHAR(10),
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    INDEX idx_pathway_id (pathway_id)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_skills (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    skill_name VARCHAR(255),
+    skill_description TEXT,
+    importance DECIMAL(3,2),
+    level DECIMAL(10,6),
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    INDEX idx_skill_name (skill_name)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_knowledge (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    knowledge_name VARCHAR(255),
+    knowledge_description TEXT,
+    importance DECIMAL(3,2),
+    level DECIMAL(10,6),
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    INDEX idx_knowledge_name (knowledge_name)
+);
+
+CREATE TABLE IF NOT EXISTS occupation_abilities (
+    id INT AUTO_INCREMENT PRIMARY KEY,
+    occupation_id VARCHAR(20),
+    ability_name VARCHAR(255),
+    ability_description TEXT,
+    importance DECIMAL(3,2),
+    level DECIMAL(10,6),
+    FOREIGN KEY (occupation_id) REFERENCES occupations(id) ON DELETE CASCADE,
+    INDEX idx_occupation_id (occupation_id),
+    INDEX idx_ability_name (ability_name)
+);
+
+-- Columns and indexes added to existing tables
+
+SET @ddl = (SELECT IF(COUNT(*) = 0, 'ALTER TABLE occupations ADD COLUMN title_slug VARCHAR(255) AFTER typical_ed_level, ADD INDEX idx_title_slug (title_slug)', 'DO 0') FROM information_schema.columns
+    WHERE table_schema = DATABASE() AND table_name = 'occupations' AND column_name = 'title_slug');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+SET @ddl = (SELECT IF(COUNT(*) = 0, 'ALTER TABLE occupation_tasks ADD FULLTEXT INDEX ft_task (task)', 'DO 0') FROM information_schema.statistics
+    WHERE table_schema = DATABASE() AND table_name = 'occupation_tasks' AND index_name = 'ft_task');
+PREPARE stmt FROM @ddl;
+EXECUTE stmt;
+DEALLOCATE PREPARE stmt;
+
+-- Backfill from the stored records. Child rows are only added for
+-- occupations that have none yet.
+
+UPDATE occupations
+SET title_slug = COALESCE(
+    NULLIF(JSON_UNQUOTE(JSON_EXTRACT(data, '$.titleSlug')), ''),
+    TRIM(BOTH '-' FROM REGEXP_REPLACE(LOWER(COALESCE(
+        NULLIF(JSON_UNQUOTE(JSON_EXTRACT(data, '$.shortTitle')), ''),
+        NULLIF(singular_title, ''),
+        title
+    )), '[^a-z0-9]+', '-'))
+)
+WHERE title_slug IS NULL;
+
+INSERT INTO occupation_alt_titles (occupation_id, title, source)
+SELECT o.id, jt.title, jt.source
+FROM occupations o,
+    JSON_TABLE(JSON_ARRAY(
+        JSON_OBJECT('source', 'lay', 'titles', COALESCE(JSON_EXTRACT(o.data, '$.layTitles'), JSON_ARRAY())),
+        JSON_OBJECT('source', 'emsi', 'titles', COALESCE(JSON_EXTRACT(o.data, '$.emsiTitles'), JSON_ARRAY()))
+    ), '$[*]' COLUMNS (
+        source VARCHAR(10) PATH '$.source',
+        NESTED PATH '$.titles[*]' COLUMNS (title VARCHAR(255) PATH '$')
+    )) jt
+WHERE jt.title IS NOT NULL
+    AND NOT EXISTS (SELECT 1 FROM occupation_alt_titles a WHERE a.occupation_id = o.id)
+ORDER BY o.id;
+
+INSERT INTO occupation_military (occupation_id, moc_code, branch, category)
+SELECT o.id, jt.code,
+    CASE SUBSTRING(jt.code, 1, 1) WHEN 'A' THEN 'Army' WHEN 'C' THEN 'Coast Guard' WHEN 'F' THEN 'Air Force' WHEN 'M' THEN 'Marine Corps' WHEN 'N' THEN 'Navy' ELSE 'unknown' END,
+    CASE SUBSTRING(jt.code, 3, 1) WHEN 'E' THEN 'enlisted' WHEN 'O' THEN 'officer' WHEN 'W' THEN 'warrant_officer' ELSE 'unknown' END
+FROM occupations o,
+    JSON_TABLE(COALESCE(JSON_EXTRACT(o.data, '$.mocs'), JSON_ARRAY()), '$[*]' COLUMNS (code VARCHAR(20) PATH '$')) jt
+WHERE NOT EXISTS (SELECT 1 FROM occupation_military m WHERE m.occupation_id = o.id)
+ORDER BY o.id;
+
+INSERT IGNORE INTO career_pathways (id, cluster_id)
+SELECT DISTINCT jt.pathway, SUBSTRING_INDEX(jt.pathway, '.', 1)
+FROM occupations o,
+    JSON_TABLE(COALESCE(JSON_EXTRACT(o.data, '$.pathways'), JSON_ARRAY()), '$[*]' COLUMNS (pathway VARCHAR(10) PATH '$')) jt;
+
+INSERT INTO occupation_pathways (occupation_id, pathway_id)
+SELECT o.id, jt.pathway
+FROM occupations o,
+    JSON_TABLE(COALESCE(JSON_EXTRACT(o.data, '$.pathways'), JSON_ARRAY()), '$[*]' COLUMNS (pathway VARCHAR(10) PATH '$')) jt
+WHERE NOT EXISTS (SELECT 1 FROM occupation_pathways p WHERE p.occupation_id = o.id)
+ORDER BY o.id;
+
+-- Career cluster and pathway names
+
+INSERT INTO career_clusters (id, name) VALUES (1, 'Advanced Manufacturing') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('1.1', 1, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('1.3', 1, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('1.5', 1, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (2, 'Construction') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('2.1', 2, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('2.2', 2, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (3, 'Supply Chain & Transportation') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('3.1', 3, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('3.5', 3, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('3.6', 3, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (4, 'Arts, Entertainment & Design') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('4.2', 4, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('4.5', 4, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('4.6', 4, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (5, 'Hospitality, Events & Tourism') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('5.1', 5, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('5.2', 5, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('5.3', 5, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('5.4', 5, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (6, 'Financial Services') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('6.1', 6, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('6.2', 6, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('6.3', 6, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('6.4', 6, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('6.5', 6, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (7, 'Education') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('7.1', 7, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('7.2', 7, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('7.4', 7, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (8, 'Healthcare & Human Services') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('8.2', 8, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('8.3', 8, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('8.4', 8, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('8.6', 8, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (9, 'Public Service & Safety') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('9.1', 9, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('9.3', 9, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('9.4', 9, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('9.5', 9, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (10, 'Agriculture') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('10.1', 10, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('10.2', 10, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('10.3', 10, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('10.4', 10, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('10.5', 10, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('10.6', 10, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (11, 'Energy & Natural Resources') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('11.1', 11, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('11.2', 11, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('11.3', 11, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('11.4', 11, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('11.5', 11, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('11.6', 11, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (12, 'Digital Technology') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('12.2', 12, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('12.3', 12, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('12.4', 12, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('12.6', 12, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (13, 'Marketing & Sales') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('13.1', 13, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('13.2', 13, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('13.3', 13, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('13.4', 13, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_clusters (id, name) VALUES (14, 'Management & Entrepreneurship') AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('14.1', 14, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('14.2', 14, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('14.3', 14, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('14.4', 14, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+INSERT INTO career_pathways (id, cluster_id, name) VALUES ('14.5', 14, NULL) AS new ON DUPLICATE KEY UPDATE name = new.name;
+
@@ -11,4 +11,11 @@ go build -o convert-jsonl main.go
 ./convert-jsonl -input occupations.jsonl -output seed_data.sql -lines 0
 
 # Custom file names
-./convert-jsonl -input data.jsonl -output output.sql -lines 100
\ No newline at end of file
+./convert-jsonl -input data.jsonl -output output.sql -lines 100
+
+# Write the upgrade script for existing databases elsewhere, or skip it
+./convert-jsonl -migration upgrade.sql
+./convert-jsonl -migration ''
+
+# Custom cluster/pathway names lookup
+./convert-jsonl -clusters career_clusters.json
\ No newline at end of file